@@ -0,0 +1,156 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// requestIDHeader carries the request ID of an HTTP request or, lowercased,
+// of a gRPC call. A client-supplied one is kept so that IDs can be
+// correlated across services.
+const requestIDHeader = "X-Request-Id"
+
+// maxRequestID bounds the length of a client-supplied request ID.
+const maxRequestID = 128
+
+// requestID returns the ID of a request whose client supplied id, which
+// may be empty: id itself, or a fresh goofy ID such as 4821-0937-5561.
+func (s *server) requestID(id string) string {
+	if id != "" && len(id) <= maxRequestID {
+		return id
+	}
+	n := s.seq.Add(1)
+	return goofy.FormatGrouped(goofy.NDigitID(fmt.Sprintf("%d-%d", s.started.UnixNano(), n), goofy.MaxDigits), 4, "-")
+}
+
+// statusWriter records the status code and body size of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// logHTTP wraps h, tagging every request with a request ID, echoed in the
+// X-Request-Id response header, and logging it once answered.
+func (s *server) logHTTP(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := s.requestID(r.Header.Get(requestIDHeader))
+		w.Header().Set(requestIDHeader, id)
+		sw := &statusWriter{ResponseWriter: w}
+		h.ServeHTTP(sw, r)
+		if sw.status == 0 {
+			sw.status = http.StatusOK
+		}
+		attrs := []any{
+			"request_id", id,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", sw.status,
+			"duration", time.Since(start),
+			"bytes", sw.bytes,
+			"client", httpClient(r),
+		}
+		if s.keys != nil {
+			label, _ := s.keys.label(httpAPIKey(r))
+			attrs = append(attrs, "key", label)
+		}
+		slog.Info("request", attrs...)
+	})
+}
+
+// grpcRequestID returns the request ID of the gRPC call with ctx and sends
+// it back to the client as response header metadata.
+func (s *server) grpcRequestID(ctx context.Context, setHeader func(metadata.MD) error) string {
+	var supplied string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if v := md.Get(requestIDHeader); len(v) > 0 {
+			supplied = v[0]
+		}
+	}
+	id := s.requestID(supplied)
+	setHeader(metadata.Pairs(requestIDHeader, id))
+	return id
+}
+
+// logCall logs a finished gRPC call to method.
+func (s *server) logCall(ctx context.Context, id, method string, start time.Time, err error) {
+	attrs := []any{
+		"request_id", id,
+		"method", method,
+		"status", status.Code(err).String(),
+		"duration", time.Since(start),
+		"client", grpcClient(ctx),
+	}
+	if s.keys != nil {
+		label, _ := s.keys.label(grpcAPIKey(ctx))
+		attrs = append(attrs, "key", label)
+	}
+	slog.Info("request", attrs...)
+}
+
+// logUnary tags and logs unary gRPC calls like logHTTP does requests.
+func (s *server) logUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	id := s.grpcRequestID(ctx, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) })
+	resp, err := handler(ctx, req)
+	s.logCall(ctx, id, info.FullMethod, start, err)
+	return resp, err
+}
+
+// logStream tags and logs streaming gRPC calls like logHTTP does requests.
+func (s *server) logStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	id := s.grpcRequestID(ss.Context(), ss.SetHeader)
+	err := handler(srv, ss)
+	s.logCall(ss.Context(), id, info.FullMethod, start, err)
+	return err
+}
@@ -0,0 +1,238 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// analyzeCommand defines the flags of "goofy analyze" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func analyzeCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	minDigits := fs.Int("min-digits", goofy.MinDigits, "analyze ID lengths from `N` symbols")
+	maxDigits := fs.Int("max-digits", goofy.MaxDigits, "analyze ID lengths up to `N` symbols")
+	buckets := fs.Int("buckets", 10, "split the ID space into `N` buckets for the histogram and chi-square test")
+	nul := fs.Bool("0", false, "read NUL-separated records instead of lines")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s analyze [options] [FILE...]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Hash the distinct records of each FILE, or of stdin if no FILE is given,\n")
+		fmt.Fprintf(os.Stderr, "and report for every ID length how many inputs collide compared to an\n")
+		fmt.Fprintf(os.Stderr, "ideal hash, and how uniformly the IDs spread over the ID space (a\n")
+		fmt.Fprintf(os.Stderr, "chi-square test over -buckets equal ranges). A histogram shows the\n")
+		fmt.Fprintf(os.Stderr, "spread at -digits. Use it to pick a -digits value for your data.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s analyze customers.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s analyze -base 36 -min-digits 4 -max-digits 8 skus.txt\n", os.Args[0])
+	}
+
+	return func() int {
+		opts, err := gen.options()
+		if err == nil && opts.Words != 0 {
+			err = fmt.Errorf("-words cannot be analyzed per ID length")
+		}
+		if err == nil && (*minDigits < goofy.MinDigits || *maxDigits > goofy.MaxDigits || *minDigits > *maxDigits) {
+			err = fmt.Errorf("-min-digits and -max-digits must satisfy %d <= min <= max <= %d", goofy.MinDigits, goofy.MaxDigits)
+		}
+		if err == nil && *buckets < 2 {
+			err = fmt.Errorf("-buckets must be at least 2, got %d", *buckets)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		files := fs.Args()
+		if len(files) == 0 {
+			files = []string{"-"}
+		}
+		c := newCorpus(opts)
+		in := inputOptions{nul: *nul, maxRecord: defaultMaxRecord}
+		for _, name := range files {
+			if err := processFile(name, in, c.add); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+		}
+		if len(c.sums) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no inputs to analyze\n")
+			return 1
+		}
+		c.report(os.Stdout, *minDigits, *maxDigits, *buckets)
+		return 0
+	}
+}
+
+// corpus holds the hashes of the distinct inputs being analyzed.
+type corpus struct {
+	opts  goofy.Options
+	read  int                 // records read, including repeats
+	seen  map[string]struct{} // distinct inputs
+	sums  []uint64            // Sum64 of each distinct input
+	trunc int                 // distinct inputs exceeding -max-bytes
+}
+
+func newCorpus(opts goofy.Options) *corpus {
+	return &corpus{opts: opts, seen: make(map[string]struct{})}
+}
+
+// add hashes input unless it was seen before; it has the signature
+// processFile expects.
+func (c *corpus) add(input string) error {
+	c.read++
+	if _, ok := c.seen[input]; ok {
+		return nil
+	}
+	c.seen[input] = struct{}{}
+	c.sums = append(c.sums, goofy.Sum64(input, c.opts))
+	if _, truncated := goofy.HashedInput(input, c.opts); truncated {
+		c.trunc++
+	}
+	return nil
+}
+
+// lengthStats are the results for one ID length.
+type lengthStats struct {
+	digits     int
+	space      uint64
+	collisions int     // distinct inputs sharing an ID with an earlier one
+	expected   float64 // collisions expected of an ideal hash
+	chi2       float64 // chi-square statistic over the buckets
+	p          float64 // probability of a chi2 at least as large by chance
+	hist       []int   // inputs per bucket
+}
+
+// analyze computes the statistics of the corpus at the given ID length,
+// or reports false if base**digits overflows 64 bits.
+func (c *corpus) analyze(digits, buckets int) (lengthStats, bool) {
+	opts := c.opts
+	opts.Digits = digits
+	if opts.Validate() != nil {
+		return lengthStats{}, false
+	}
+	st := lengthStats{digits: digits, space: opts.Space(), hist: make([]int, buckets)}
+	ids := make(map[uint64]struct{}, len(c.sums))
+	for _, h := range c.sums {
+		v := h % st.space
+		if _, ok := ids[v]; ok {
+			st.collisions++
+		} else {
+			ids[v] = struct{}{}
+		}
+		// v*buckets/space without overflowing; hi < space as v < space.
+		hi, lo := bits.Mul64(v, uint64(buckets))
+		b, _ := bits.Div64(hi, lo, st.space)
+		st.hist[b]++
+	}
+	n := uint64(len(c.sums))
+	st.expected = goofy.ExpectedCollisions(n, st.space)
+	st.chi2, st.p = chiSquare(st.hist, st.space)
+	return st, true
+}
+
+// chiSquare returns the chi-square statistic of hist against a uniform
+// spread over space IDs and its approximate p-value. The buckets need not
+// be of equal size when space is not a multiple of their number.
+func chiSquare(hist []int, space uint64) (chi2, p float64) {
+	n := 0
+	for _, k := range hist {
+		n += k
+	}
+	nb := uint64(len(hist))
+	for i, k := range hist {
+		// Bucket i holds the values v with v*nb/space == i.
+		var size float64
+		if space <= math.MaxUint64/nb {
+			lo := (uint64(i)*space + nb - 1) / nb
+			hi := (uint64(i+1)*space + nb - 1) / nb
+			size = float64(hi - lo)
+		} else {
+			size = float64(space) / float64(nb)
+		}
+		want := float64(n) * size / float64(space)
+		if want > 0 {
+			d := float64(k) - want
+			chi2 += d * d / want
+		}
+	}
+	return chi2, chiSquareP(chi2, len(hist)-1)
+}
+
+// chiSquareP approximates the upper tail probability of the chi-square
+// distribution with df degrees of freedom at x (Wilson-Hilferty).
+func chiSquareP(x float64, df int) float64 {
+	k := float64(df)
+	z := (math.Cbrt(x/k) - (1 - 2/(9*k))) / math.Sqrt(2/(9*k))
+	return 0.5 * math.Erfc(z/math.Sqrt2)
+}
+
+// report writes the analysis of the corpus for ID lengths from to to and
+// the histogram at the length selected by -digits.
+func (c *corpus) report(w io.Writer, from, to, buckets int) {
+	fmt.Fprintf(w, "inputs: %d distinct of %d read", len(c.sums), c.read)
+	if c.trunc > 0 {
+		fmt.Fprintf(w, ", %d longer than -max-bytes", c.trunc)
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "digits\tIDs\tcollisions\texpected\tchi2(%d)\tp\t\n", buckets-1)
+	var hist *lengthStats
+	for d := from; d <= to; d++ {
+		st, ok := c.analyze(d, buckets)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(tw, "%d\t%d\t%d\t%.1f\t%.1f\t%.3f\t\n", st.digits, st.space, st.collisions, st.expected, st.chi2, st.p)
+		if d == c.opts.Digits || (c.opts.Digits == 0 && d == goofy.DefaultDigits) {
+			hist = &st
+		}
+	}
+	tw.Flush()
+	if hist == nil {
+		return
+	}
+
+	fmt.Fprintf(w, "\nhistogram at %d digits:\n", hist.digits)
+	peak := 0
+	for _, k := range hist.hist {
+		peak = max(peak, k)
+	}
+	for i, k := range hist.hist {
+		bar := 0
+		if peak > 0 {
+			bar = k * 40 / peak
+		}
+		fmt.Fprintf(w, "  %3d-%3d%%  %8d  %s\n", i*100/buckets, (i+1)*100/buckets, k, strings.Repeat("#", bar))
+	}
+}
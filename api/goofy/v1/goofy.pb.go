@@ -0,0 +1,295 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: goofy/v1/goofy.proto
+
+package goofyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GenerateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Input string                 `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	// namespace, if set, selects the ID space the ID is generated and
+	// registered in instead of the server's -namespace.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_goofy_v1_goofy_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goofy_v1_goofy_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_goofy_v1_goofy_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GenerateRequest) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *GenerateRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+type GenerateResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Input     string                 `protobuf:"bytes,1,opt,name=input,proto3" json:"input,omitempty"`
+	Id        string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Namespace string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// error, set by BulkGenerate in place of id, says why input got no ID:
+	// with -registry, that its ID is registered to another input.
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_goofy_v1_goofy_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_goofy_v1_goofy_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_goofy_v1_goofy_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GenerateResponse) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *GenerateResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GenerateResponse) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GenerateResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GenerateStreamRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Inputs []string               `protobuf:"bytes,1,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// namespace, if set, selects the ID space of all inputs, as in
+	// GenerateRequest.
+	Namespace     string `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateStreamRequest) Reset() {
+	*x = GenerateStreamRequest{}
+	mi := &file_goofy_v1_goofy_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateStreamRequest) ProtoMessage() {}
+
+func (x *GenerateStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_goofy_v1_goofy_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateStreamRequest.ProtoReflect.Descriptor instead.
+func (*GenerateStreamRequest) Descriptor() ([]byte, []int) {
+	return file_goofy_v1_goofy_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateStreamRequest) GetInputs() []string {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *GenerateStreamRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+var File_goofy_v1_goofy_proto protoreflect.FileDescriptor
+
+const file_goofy_v1_goofy_proto_rawDesc = "" +
+	"\n" +
+	"\x14goofy/v1/goofy.proto\x12\bgoofy.v1\"E\n" +
+	"\x0fGenerateRequest\x12\x14\n" +
+	"\x05input\x18\x01 \x01(\tR\x05input\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\"l\n" +
+	"\x10GenerateResponse\x12\x14\n" +
+	"\x05input\x18\x01 \x01(\tR\x05input\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"M\n" +
+	"\x15GenerateStreamRequest\x12\x16\n" +
+	"\x06inputs\x18\x01 \x03(\tR\x06inputs\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace2\xea\x01\n" +
+	"\tIDService\x12A\n" +
+	"\bGenerate\x12\x19.goofy.v1.GenerateRequest\x1a\x1a.goofy.v1.GenerateResponse\x12O\n" +
+	"\x0eGenerateStream\x12\x1f.goofy.v1.GenerateStreamRequest\x1a\x1a.goofy.v1.GenerateResponse0\x01\x12I\n" +
+	"\fBulkGenerate\x12\x19.goofy.v1.GenerateRequest\x1a\x1a.goofy.v1.GenerateResponse(\x010\x01B1Z/github.com/al-maisan/goofy/api/goofy/v1;goofyv1b\x06proto3"
+
+var (
+	file_goofy_v1_goofy_proto_rawDescOnce sync.Once
+	file_goofy_v1_goofy_proto_rawDescData []byte
+)
+
+func file_goofy_v1_goofy_proto_rawDescGZIP() []byte {
+	file_goofy_v1_goofy_proto_rawDescOnce.Do(func() {
+		file_goofy_v1_goofy_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_goofy_v1_goofy_proto_rawDesc), len(file_goofy_v1_goofy_proto_rawDesc)))
+	})
+	return file_goofy_v1_goofy_proto_rawDescData
+}
+
+var file_goofy_v1_goofy_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_goofy_v1_goofy_proto_goTypes = []any{
+	(*GenerateRequest)(nil),       // 0: goofy.v1.GenerateRequest
+	(*GenerateResponse)(nil),      // 1: goofy.v1.GenerateResponse
+	(*GenerateStreamRequest)(nil), // 2: goofy.v1.GenerateStreamRequest
+}
+var file_goofy_v1_goofy_proto_depIdxs = []int32{
+	0, // 0: goofy.v1.IDService.Generate:input_type -> goofy.v1.GenerateRequest
+	2, // 1: goofy.v1.IDService.GenerateStream:input_type -> goofy.v1.GenerateStreamRequest
+	0, // 2: goofy.v1.IDService.BulkGenerate:input_type -> goofy.v1.GenerateRequest
+	1, // 3: goofy.v1.IDService.Generate:output_type -> goofy.v1.GenerateResponse
+	1, // 4: goofy.v1.IDService.GenerateStream:output_type -> goofy.v1.GenerateResponse
+	1, // 5: goofy.v1.IDService.BulkGenerate:output_type -> goofy.v1.GenerateResponse
+	3, // [3:6] is the sub-list for method output_type
+	0, // [0:3] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_goofy_v1_goofy_proto_init() }
+func file_goofy_v1_goofy_proto_init() {
+	if File_goofy_v1_goofy_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_goofy_v1_goofy_proto_rawDesc), len(file_goofy_v1_goofy_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_goofy_v1_goofy_proto_goTypes,
+		DependencyIndexes: file_goofy_v1_goofy_proto_depIdxs,
+		MessageInfos:      file_goofy_v1_goofy_proto_msgTypes,
+	}.Build()
+	File_goofy_v1_goofy_proto = out.File
+	file_goofy_v1_goofy_proto_goTypes = nil
+	file_goofy_v1_goofy_proto_depIdxs = nil
+}
@@ -0,0 +1,239 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: goofy/v1/goofy.proto
+
+package goofyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	IDService_Generate_FullMethodName       = "/goofy.v1.IDService/Generate"
+	IDService_GenerateStream_FullMethodName = "/goofy.v1.IDService/GenerateStream"
+	IDService_BulkGenerate_FullMethodName   = "/goofy.v1.IDService/BulkGenerate"
+)
+
+// IDServiceClient is the client API for IDService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// IDService generates goofy IDs with the options the server was started with.
+type IDServiceClient interface {
+	// Generate returns the ID of a single input.
+	Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error)
+	// GenerateStream returns the IDs of several inputs, one response per
+	// input, in request order.
+	GenerateStream(ctx context.Context, in *GenerateStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateResponse], error)
+	// BulkGenerate returns the ID of every input streamed by the client, one
+	// response per request, in request order. The server reads the next
+	// request only once the response to the previous one is sent, so a
+	// client that stops reading is slowed down by HTTP/2 flow control
+	// instead of making the server buffer: millions of inputs can be pushed
+	// over a single call with bounded memory on both sides. With -registry,
+	// an input whose ID is registered to another input is answered with the
+	// conflict in error instead of an id, and the stream goes on.
+	BulkGenerate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GenerateRequest, GenerateResponse], error)
+}
+
+type iDServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewIDServiceClient(cc grpc.ClientConnInterface) IDServiceClient {
+	return &iDServiceClient{cc}
+}
+
+func (c *iDServiceClient) Generate(ctx context.Context, in *GenerateRequest, opts ...grpc.CallOption) (*GenerateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GenerateResponse)
+	err := c.cc.Invoke(ctx, IDService_Generate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *iDServiceClient) GenerateStream(ctx context.Context, in *GenerateStreamRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GenerateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IDService_ServiceDesc.Streams[0], IDService_GenerateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateStreamRequest, GenerateResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDService_GenerateStreamClient = grpc.ServerStreamingClient[GenerateResponse]
+
+func (c *iDServiceClient) BulkGenerate(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GenerateRequest, GenerateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &IDService_ServiceDesc.Streams[1], IDService_BulkGenerate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GenerateRequest, GenerateResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDService_BulkGenerateClient = grpc.BidiStreamingClient[GenerateRequest, GenerateResponse]
+
+// IDServiceServer is the server API for IDService service.
+// All implementations must embed UnimplementedIDServiceServer
+// for forward compatibility.
+//
+// IDService generates goofy IDs with the options the server was started with.
+type IDServiceServer interface {
+	// Generate returns the ID of a single input.
+	Generate(context.Context, *GenerateRequest) (*GenerateResponse, error)
+	// GenerateStream returns the IDs of several inputs, one response per
+	// input, in request order.
+	GenerateStream(*GenerateStreamRequest, grpc.ServerStreamingServer[GenerateResponse]) error
+	// BulkGenerate returns the ID of every input streamed by the client, one
+	// response per request, in request order. The server reads the next
+	// request only once the response to the previous one is sent, so a
+	// client that stops reading is slowed down by HTTP/2 flow control
+	// instead of making the server buffer: millions of inputs can be pushed
+	// over a single call with bounded memory on both sides. With -registry,
+	// an input whose ID is registered to another input is answered with the
+	// conflict in error instead of an id, and the stream goes on.
+	BulkGenerate(grpc.BidiStreamingServer[GenerateRequest, GenerateResponse]) error
+	mustEmbedUnimplementedIDServiceServer()
+}
+
+// UnimplementedIDServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedIDServiceServer struct{}
+
+func (UnimplementedIDServiceServer) Generate(context.Context, *GenerateRequest) (*GenerateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Generate not implemented")
+}
+func (UnimplementedIDServiceServer) GenerateStream(*GenerateStreamRequest, grpc.ServerStreamingServer[GenerateResponse]) error {
+	return status.Error(codes.Unimplemented, "method GenerateStream not implemented")
+}
+func (UnimplementedIDServiceServer) BulkGenerate(grpc.BidiStreamingServer[GenerateRequest, GenerateResponse]) error {
+	return status.Error(codes.Unimplemented, "method BulkGenerate not implemented")
+}
+func (UnimplementedIDServiceServer) mustEmbedUnimplementedIDServiceServer() {}
+func (UnimplementedIDServiceServer) testEmbeddedByValue()                   {}
+
+// UnsafeIDServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to IDServiceServer will
+// result in compilation errors.
+type UnsafeIDServiceServer interface {
+	mustEmbedUnimplementedIDServiceServer()
+}
+
+func RegisterIDServiceServer(s grpc.ServiceRegistrar, srv IDServiceServer) {
+	// If the following call panics, it indicates UnimplementedIDServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&IDService_ServiceDesc, srv)
+}
+
+func _IDService_Generate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IDServiceServer).Generate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: IDService_Generate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IDServiceServer).Generate(ctx, req.(*GenerateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _IDService_GenerateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GenerateStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(IDServiceServer).GenerateStream(m, &grpc.GenericServerStream[GenerateStreamRequest, GenerateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDService_GenerateStreamServer = grpc.ServerStreamingServer[GenerateResponse]
+
+func _IDService_BulkGenerate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(IDServiceServer).BulkGenerate(&grpc.GenericServerStream[GenerateRequest, GenerateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type IDService_BulkGenerateServer = grpc.BidiStreamingServer[GenerateRequest, GenerateResponse]
+
+// IDService_ServiceDesc is the grpc.ServiceDesc for IDService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var IDService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "goofy.v1.IDService",
+	HandlerType: (*IDServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Generate",
+			Handler:    _IDService_Generate_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GenerateStream",
+			Handler:       _IDService_GenerateStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BulkGenerate",
+			Handler:       _IDService_BulkGenerate_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "goofy/v1/goofy.proto",
+}
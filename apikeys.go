@@ -0,0 +1,163 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyHeader carries the API key of an HTTP request or, lowercased, of a
+// gRPC call; "Authorization: Bearer KEY" works too.
+const apiKeyHeader = "X-Api-Key"
+
+// apiKeys maps the SHA-256 digests of the accepted API keys to their
+// labels. Comparing digests keeps lookups from leaking key prefixes
+// through timing.
+type apiKeys map[[sha256.Size]byte]string
+
+// loadAPIKeys reads an -api-keys-file: one "LABEL KEY" pair per line,
+// blank lines and lines starting with # ignored. Labels name keys in
+// metrics and logs, so that the keys themselves are never shown.
+func loadAPIKeys(path string) (apiKeys, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	keys := apiKeys{}
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: want LABEL KEY", path, n)
+		}
+		digest := sha256.Sum256([]byte(fields[1]))
+		if label, dup := keys[digest]; dup {
+			return nil, fmt.Errorf("%s:%d: key of %q repeated", path, n, label)
+		}
+		keys[digest] = fields[0]
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: no API keys", path)
+	}
+	return keys, nil
+}
+
+// label returns the label of key, if it is accepted.
+func (k apiKeys) label(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	label, ok := k[sha256.Sum256([]byte(key))]
+	return label, ok
+}
+
+// bearer returns the token of an "Authorization: Bearer TOKEN" value.
+func bearer(auth string) string {
+	scheme, token, ok := strings.Cut(auth, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// httpAPIKey returns the API key r was sent with, if any.
+func httpAPIKey(r *http.Request) string {
+	if key := r.Header.Get(apiKeyHeader); key != "" {
+		return key
+	}
+	return bearer(r.Header.Get("Authorization"))
+}
+
+// grpcAPIKey returns the API key the gRPC call with ctx was sent with, if
+// any.
+func grpcAPIKey(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get(apiKeyHeader); len(v) > 0 {
+		return v[0]
+	}
+	if v := md.Get("authorization"); len(v) > 0 {
+		return bearer(v[0])
+	}
+	return ""
+}
+
+// requireKeyHTTP wraps h, answering 401 Unauthorized to requests without
+// an accepted API key once -api-keys-file is set.
+func (s *server) requireKeyHTTP(h http.HandlerFunc) http.HandlerFunc {
+	if s.keys == nil {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		label, ok := s.keys.label(httpAPIKey(r))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="goofy"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		s.metrics.keyRequests.WithLabelValues(label).Inc()
+		h(w, r)
+	}
+}
+
+// checkKeyGRPC returns an Unauthenticated error unless the gRPC call with
+// ctx carries an accepted API key.
+func (s *server) checkKeyGRPC(ctx context.Context) error {
+	label, ok := s.keys.label(grpcAPIKey(ctx))
+	if !ok {
+		return status.Error(codes.Unauthenticated, "missing or invalid API key")
+	}
+	s.metrics.keyRequests.WithLabelValues(label).Inc()
+	return nil
+}
+
+// keyUnary rejects unary gRPC calls without an accepted API key.
+func (s *server) keyUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.checkKeyGRPC(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// keyStream rejects streaming gRPC calls without an accepted API key.
+func (s *server) keyStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkKeyGRPC(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,168 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// benchKey keys the algorithms that require one while benchmarking.
+var benchKey = []byte("goofy bench key!") // 16 bytes, as siphash needs
+
+// benchCommand defines the flags of "goofy bench" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func benchCommand(fs *flag.FlagSet) func() int {
+	algos := fs.String("algos", strings.Join(goofy.Algorithms(), ","), "comma-separated hash `ALGORITHMS` to compare")
+	n := fs.Int("n", 100000, "benchmark on `N` synthetic inputs")
+	size := fs.Int("size", 32, "length of the synthetic inputs in `BYTES`")
+	maxBytes := fs.Int("max-bytes", goofy.MaxBytes, "hash at most the first `N` bytes of each input (0 for unlimited)")
+	duration := fs.Duration("time", time.Second, "run each algorithm on each data set for at least `DURATION`")
+	nul := fs.Bool("0", false, "read NUL-separated records from FILEs instead of lines")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s bench [options] [FILE...]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Measure how many inputs per second, and how many MB of input per second,\n")
+		fmt.Fprintf(os.Stderr, "each hash algorithm turns into IDs on a single core: on -n synthetic\n")
+		fmt.Fprintf(os.Stderr, "inputs of -size bytes and on the records of each FILE, which are loaded\n")
+		fmt.Fprintf(os.Stderr, "into memory first.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s bench\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s bench -algos fnv1a,xxhash64 -max-bytes 0 sample.txt\n", os.Args[0])
+	}
+
+	return func() int {
+		names := strings.Split(*algos, ",")
+		var err error
+		for _, name := range names {
+			if _, herr := goofy.NewHasher(name, keyFor(name)); herr != nil && err == nil {
+				err = herr
+			}
+		}
+		switch {
+		case err != nil:
+		case *n < 1 || *size < 1:
+			err = fmt.Errorf("-n and -size must be positive")
+		case *maxBytes < 0:
+			err = fmt.Errorf("-max-bytes must not be negative, got %d", *maxBytes)
+		case *duration <= 0:
+			err = fmt.Errorf("-time must be positive, got %v", *duration)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		sets := []benchSet{{fmt.Sprintf("synthetic %dB", *size), syntheticInputs(*n, *size)}}
+		in := inputOptions{nul: *nul, maxRecord: defaultMaxRecord}
+		for _, name := range fs.Args() {
+			set := benchSet{name: name}
+			err := processFile(name, in, func(s string) error {
+				set.inputs = append(set.inputs, s)
+				return nil
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+			if len(set.inputs) == 0 {
+				fmt.Fprintf(os.Stderr, "Error: %s holds no inputs\n", name)
+				return 1
+			}
+			sets = append(sets, set)
+		}
+
+		mb := *maxBytes
+		if mb == 0 {
+			mb = goofy.NoTruncation
+		}
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(tw, "data\talgorithm\tinputs/s\tMB/s\t\n")
+		for _, set := range sets {
+			for _, name := range names {
+				opts := goofy.Options{Algo: name, Key: keyFor(name), MaxBytes: mb}
+				perSec, mbPerSec := set.run(opts, *duration)
+				fmt.Fprintf(tw, "%s\t%s\t%.0f\t%.1f\t\n", set.name, name, perSec, mbPerSec)
+			}
+		}
+		tw.Flush()
+		return 0
+	}
+}
+
+// keyFor returns the key to benchmark the named algorithm with.
+func keyFor(name string) []byte {
+	if goofy.Keyed(name) {
+		return benchKey
+	}
+	return nil
+}
+
+// syntheticInputs returns n pseudo-random alphanumeric strings of size
+// bytes, the same ones on every run.
+func syntheticInputs(n, size int) []string {
+	const alphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	r := rand.New(rand.NewPCG(1, 2))
+	inputs := make([]string, n)
+	buf := make([]byte, size)
+	for i := range inputs {
+		for j := range buf {
+			buf[j] = alphabet[r.IntN(len(alphabet))]
+		}
+		inputs[i] = string(buf)
+	}
+	return inputs
+}
+
+// benchSet is a named data set to benchmark on.
+type benchSet struct {
+	name   string
+	inputs []string
+}
+
+// run generates the IDs of the set's inputs under opts, in whole passes,
+// until at least d has passed, and returns the inputs and MB of input
+// processed per second.
+func (b benchSet) run(opts goofy.Options, d time.Duration) (perSec, mbPerSec float64) {
+	var n, bytes, sink int
+	start := time.Now()
+	for time.Since(start) < d {
+		for _, s := range b.inputs {
+			sink += len(goofy.Generate(s, opts))
+			bytes += len(s)
+		}
+		n += len(b.inputs)
+	}
+	secs := time.Since(start).Seconds()
+	if sink == 0 {
+		panic("goofy: no IDs generated") // keeps the loop from being optimized away
+	}
+	return float64(n) / secs, float64(bytes) / 1e6 / secs
+}
@@ -0,0 +1,84 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// birthdayRisks are the collision probabilities "goofy birthday" lists
+// the safe number of inputs for.
+var birthdayRisks = []float64{0.001, 0.01, 0.1, 0.5}
+
+// birthdayCommand defines the flags of "goofy birthday" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func birthdayCommand(fs *flag.FlagSet) func() int {
+	n := fs.Uint64("n", 0, "number of distinct inputs `COUNT`")
+	digits := fs.Int("digits", goofy.DefaultDigits, fmt.Sprintf("ID length in `N` digits (%d-%d), or symbols with -base", goofy.MinDigits, goofy.MaxDigits))
+	base := fs.Int("base", goofy.DefaultBase, fmt.Sprintf("IDs in base `B`: one of %v", goofy.Bases()))
+	words := fs.Int("words", 0, fmt.Sprintf("IDs of `N` words and a number (%d-%d)", goofy.MinWords, goofy.MaxWords))
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s birthday -n COUNT [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Compute how likely COUNT distinct inputs are to collide in the ID space\n")
+		fmt.Fprintf(os.Stderr, "selected by -digits, -base or -words, assuming a uniform hash (see the\n")
+		fmt.Fprintf(os.Stderr, "birthday problem), and how many inputs keep the risk low.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s birthday -n 10000\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s birthday -n 1000000000 -digits 12\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+		opts := goofy.Options{Digits: *digits, Base: *base, Words: *words}
+		err := opts.Validate()
+		if err == nil && *n == 0 {
+			err = fmt.Errorf("missing required -n COUNT")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		space := opts.Space()
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(tw, "IDs:\t%d\n", space)
+		fmt.Fprintf(tw, "inputs:\t%d\n", *n)
+		fmt.Fprintf(tw, "expected collisions:\t%.4g\n", goofy.ExpectedCollisions(*n, space))
+		fmt.Fprintf(tw, "P(at least one):\t%.4g%%\n", 100*goofy.CollisionProbability(*n, space))
+		for _, p := range birthdayRisks {
+			fmt.Fprintf(tw, "inputs for %g%% risk:\t%d\n", 100*p, goofy.SafeInputs(space, p))
+		}
+		tw.Flush()
+		return 0
+	}
+}
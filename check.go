@@ -0,0 +1,70 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// checkCommand defines the flags of "goofy check" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func checkCommand(fs *flag.FlagSet) func() int {
+	scheme := fs.String("check-digit", "luhn", "check digit `SCHEME` the IDs were generated with: "+strings.Join(goofy.CheckDigitSchemes(), ", "))
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s check [options] <ID>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Validate the check digit of IDs generated with -check-digit, catching\n")
+		fmt.Fprintf(os.Stderr, "mistyped digits and swapped neighbours without knowing the input.\n")
+		fmt.Fprintf(os.Stderr, "IDs may be given plain (2591444) or spaced (\"25 91 44 4\").\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - all IDs are valid\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed ID\n")
+		fmt.Fprintf(os.Stderr, "  2 - check digit mismatch\n")
+	}
+
+	return func() int {
+		if fs.NArg() < 1 {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument <ID>\n\n")
+			fs.Usage()
+			return 1
+		}
+
+		code := 0
+		for _, id := range fs.Args() {
+			ok, err := goofy.ValidCheckDigit(*scheme, id)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 1
+			}
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: ID %s fails the %s check\n", id, *scheme)
+				code = 2
+			}
+		}
+		return code
+	}
+}
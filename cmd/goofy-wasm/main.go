@@ -0,0 +1,248 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build js && wasm
+
+// Command goofy-wasm exposes the goofy package to JavaScript, so web
+// frontends compute the very same IDs client-side. Built with
+//
+//	GOOS=js GOARCH=wasm go build -o goofy.wasm ./cmd/goofy-wasm
+//
+// and run with Go's wasm_exec.js, it defines a global goofy object:
+//
+//	goofy.sixDigitID("hello world!")                   // "259144"
+//	goofy.generate("hello world!", {spaced: true})      // "25 91 44"
+//	goofy.verify("hello world!", "25 91 44", {})        // true
+//	goofy.formatGrouped("259144", 3, "-")              // "259-144"
+//
+// Options are plain objects with the fields of goofy.Options in
+// camelCase (digits, base, spaced, algo, salt, namespace, ...); key is a
+// string and at a Date. Invalid options and unknown fields throw.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"syscall/js"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// optionSetters set the goofy.Options field named by a JS option.
+var optionSetters = map[string]func(opts *goofy.Options, v js.Value) error{
+	"spaced":       boolOption(func(o *goofy.Options, b bool) { o.Spaced = b }),
+	"group":        intOption(func(o *goofy.Options, n int) { o.Group = n }),
+	"sep":          stringOption(func(o *goofy.Options, s string) { o.Sep = s }),
+	"digits":       intOption(func(o *goofy.Options, n int) { o.Digits = n }),
+	"base":         intOption(func(o *goofy.Options, n int) { o.Base = n }),
+	"alphabet":     stringOption(func(o *goofy.Options, s string) { o.Alphabet = s }),
+	"nato":         boolOption(func(o *goofy.Options, b bool) { o.NATO = b }),
+	"words":        intOption(func(o *goofy.Options, n int) { o.Words = n }),
+	"checkDigit":   stringOption(func(o *goofy.Options, s string) { o.CheckDigit = s }),
+	"normalize":    stringOption(func(o *goofy.Options, s string) { o.Normalize = s }),
+	"fold":         boolOption(func(o *goofy.Options, b bool) { o.Fold = b }),
+	"trim":         boolOption(func(o *goofy.Options, b bool) { o.Trim = b }),
+	"squashSpaces": boolOption(func(o *goofy.Options, b bool) { o.SquashSpaces = b }),
+	"maxBytes":     intOption(func(o *goofy.Options, n int) { o.MaxBytes = n }),
+	"algo":         stringOption(func(o *goofy.Options, s string) { o.Algo = s }),
+	"key":          stringOption(func(o *goofy.Options, s string) { o.Key = []byte(s) }),
+	"salt":         stringOption(func(o *goofy.Options, s string) { o.Salt = s }),
+	"namespace":    stringOption(func(o *goofy.Options, s string) { o.Namespace = s }),
+	"construction": intOption(func(o *goofy.Options, n int) { o.Construction = n }),
+	"counter":      intOption(func(o *goofy.Options, n int) { o.Counter = n }),
+	"rotate":       stringOption(func(o *goofy.Options, s string) { o.Rotate = s }),
+	"at": func(o *goofy.Options, v js.Value) error {
+		if !v.InstanceOf(js.Global().Get("Date")) {
+			return errors.New("must be a Date")
+		}
+		o.At = time.UnixMilli(int64(v.Call("getTime").Float()))
+		return nil
+	},
+}
+
+func boolOption(set func(*goofy.Options, bool)) func(*goofy.Options, js.Value) error {
+	return func(o *goofy.Options, v js.Value) error {
+		if v.Type() != js.TypeBoolean {
+			return errors.New("must be a boolean")
+		}
+		set(o, v.Bool())
+		return nil
+	}
+}
+
+func intOption(set func(*goofy.Options, int)) func(*goofy.Options, js.Value) error {
+	return func(o *goofy.Options, v js.Value) error {
+		if v.Type() != js.TypeNumber || v.Float() != float64(v.Int()) {
+			return errors.New("must be an integer")
+		}
+		set(o, v.Int())
+		return nil
+	}
+}
+
+func stringOption(set func(*goofy.Options, string)) func(*goofy.Options, js.Value) error {
+	return func(o *goofy.Options, v js.Value) error {
+		if v.Type() != js.TypeString {
+			return errors.New("must be a string")
+		}
+		set(o, v.String())
+		return nil
+	}
+}
+
+// parseOptions converts a JS options object, which may be undefined or
+// null for the defaults, and validates the result.
+func parseOptions(v js.Value) (goofy.Options, error) {
+	var opts goofy.Options
+	if v.IsUndefined() || v.IsNull() {
+		return opts, nil
+	}
+	if v.Type() != js.TypeObject {
+		return opts, errors.New("options must be an object")
+	}
+	keys := js.Global().Get("Object").Call("keys", v)
+	for i := range keys.Length() {
+		name := keys.Index(i).String()
+		set, ok := optionSetters[name]
+		if !ok {
+			return opts, fmt.Errorf("unknown option %q", name)
+		}
+		if err := set(&opts, v.Get(name)); err != nil {
+			return opts, fmt.Errorf("option %s %w", name, err)
+		}
+	}
+	return opts, opts.Validate()
+}
+
+// arg returns the i-th argument, which must be a string.
+func arg(args []js.Value, i int, name string) (string, error) {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return "", fmt.Errorf("%s must be a string", name)
+	}
+	return args[i].String(), nil
+}
+
+// optionsArg returns the options passed as the i-th argument, if any.
+func optionsArg(args []js.Value, i int) (goofy.Options, error) {
+	if i >= len(args) {
+		return goofy.Options{}, nil
+	}
+	return parseOptions(args[i])
+}
+
+// jsStrings converts ss to a JS array.
+func jsStrings(ss []string) js.Value {
+	a := make([]any, len(ss))
+	for i, s := range ss {
+		a[i] = s
+	}
+	return js.ValueOf(a)
+}
+
+// throwing wraps fn, which returns its result or an error, as a JS
+// function throwing an Error for the latter: Go cannot throw into JS
+// itself, so a small JS shim does.
+func throwing(fn func(args []js.Value) (any, error)) js.Value {
+	f := js.FuncOf(func(_ js.Value, args []js.Value) any {
+		v, err := fn(args)
+		if err != nil {
+			return js.Global().Get("Error").New(err.Error())
+		}
+		return v
+	})
+	shim := js.Global().Get("Function").New("f", "return function(...args) { const r = f(...args); if (r instanceof Error) throw r; return r; }")
+	return shim.Invoke(f)
+}
+
+func main() {
+	api := map[string]any{
+		"sixDigitID": throwing(func(args []js.Value) (any, error) {
+			s, err := arg(args, 0, "input")
+			if err != nil {
+				return nil, err
+			}
+			return goofy.SixDigitID(s), nil
+		}),
+		"generate": throwing(func(args []js.Value) (any, error) {
+			s, err := arg(args, 0, "input")
+			if err != nil {
+				return nil, err
+			}
+			opts, err := optionsArg(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return goofy.Generate(s, opts), nil
+		}),
+		"verify": throwing(func(args []js.Value) (any, error) {
+			s, err := arg(args, 0, "input")
+			if err != nil {
+				return nil, err
+			}
+			id, err := arg(args, 1, "id")
+			if err != nil {
+				return nil, err
+			}
+			opts, err := optionsArg(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return goofy.Verify(s, id, opts), nil
+		}),
+		"validate": throwing(func(args []js.Value) (any, error) {
+			_, err := optionsArg(args, 0)
+			return js.Undefined(), err
+		}),
+		"formatSpaced": throwing(func(args []js.Value) (any, error) {
+			id, err := arg(args, 0, "id")
+			if err != nil {
+				return nil, err
+			}
+			return goofy.FormatSpaced(id), nil
+		}),
+		"formatGrouped": throwing(func(args []js.Value) (any, error) {
+			id, err := arg(args, 0, "id")
+			if err != nil {
+				return nil, err
+			}
+			if len(args) < 2 || args[1].Type() != js.TypeNumber || args[1].Int() < 1 {
+				return nil, errors.New("size must be a positive integer")
+			}
+			sep := goofy.DefaultSep
+			if len(args) > 2 {
+				if sep, err = arg(args, 2, "sep"); err != nil {
+					return nil, err
+				}
+			}
+			return goofy.FormatGrouped(id, args[1].Int(), sep), nil
+		}),
+		"formatNATO": throwing(func(args []js.Value) (any, error) {
+			id, err := arg(args, 0, "id")
+			if err != nil {
+				return nil, err
+			}
+			return goofy.FormatNATO(id), nil
+		}),
+		"algorithms": throwing(func([]js.Value) (any, error) {
+			return jsStrings(goofy.Algorithms()), nil
+		}),
+	}
+	js.Global().Set("goofy", js.ValueOf(api))
+	select {} // keep the functions alive
+}
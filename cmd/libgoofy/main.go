@@ -0,0 +1,171 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Command libgoofy exports the goofy package as a C library, so non-Go
+// services (Python via ctypes, C, C++) embed the exact same algorithm.
+// Built with
+//
+//	go build -buildmode=c-shared -o libgoofy.so ./cmd/libgoofy
+//
+// it yields libgoofy.so and the header libgoofy.h declaring
+//
+//	char *goofy_id(char *s);
+//	char *goofy_id_opts(char *s, char *options, char **errOut);
+//	void goofy_free(char *p);
+//
+// Strings are NUL-terminated UTF-8. options is a JSON object with the
+// fields of goofy.Options in camelCase, e.g. {"digits":8,"spaced":true};
+// key is a string and at an RFC 3339 time. Returned strings, including
+// error messages, are allocated with malloc and must be released with
+// goofy_free.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	"unsafe"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// options are the JSON options of goofy_id_opts.
+type options struct {
+	Spaced       bool      `json:"spaced"`
+	Group        int       `json:"group"`
+	Sep          string    `json:"sep"`
+	Digits       int       `json:"digits"`
+	Base         int       `json:"base"`
+	Alphabet     string    `json:"alphabet"`
+	NATO         bool      `json:"nato"`
+	Words        int       `json:"words"`
+	CheckDigit   string    `json:"checkDigit"`
+	Normalize    string    `json:"normalize"`
+	Fold         bool      `json:"fold"`
+	Trim         bool      `json:"trim"`
+	SquashSpaces bool      `json:"squashSpaces"`
+	MaxBytes     int       `json:"maxBytes"`
+	Algo         string    `json:"algo"`
+	Key          string    `json:"key"`
+	Salt         string    `json:"salt"`
+	Namespace    string    `json:"namespace"`
+	Construction int       `json:"construction"`
+	Counter      int       `json:"counter"`
+	Rotate       string    `json:"rotate"`
+	At           time.Time `json:"at"`
+}
+
+// parseOptions decodes and validates JSON options; empty means the
+// defaults. Unknown fields are rejected to catch typos.
+func parseOptions(data string) (goofy.Options, error) {
+	var o options
+	if data != "" {
+		dec := json.NewDecoder(bytes.NewReader([]byte(data)))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&o); err != nil {
+			return goofy.Options{}, fmt.Errorf("options: %w", err)
+		}
+	}
+	opts := goofy.Options{
+		Spaced:       o.Spaced,
+		Group:        o.Group,
+		Sep:          o.Sep,
+		Digits:       o.Digits,
+		Base:         o.Base,
+		Alphabet:     o.Alphabet,
+		NATO:         o.NATO,
+		Words:        o.Words,
+		CheckDigit:   o.CheckDigit,
+		Normalize:    o.Normalize,
+		Fold:         o.Fold,
+		Trim:         o.Trim,
+		SquashSpaces: o.SquashSpaces,
+		MaxBytes:     o.MaxBytes,
+		Algo:         o.Algo,
+		Salt:         o.Salt,
+		Namespace:    o.Namespace,
+		Construction: o.Construction,
+		Counter:      o.Counter,
+		Rotate:       o.Rotate,
+		At:           o.At,
+	}
+	if o.Key != "" {
+		opts.Key = []byte(o.Key)
+	}
+	return opts, opts.Validate()
+}
+
+// generate returns the ID of s under the JSON options.
+func generate(s, data string) (string, error) {
+	opts, err := parseOptions(data)
+	if err != nil {
+		return "", err
+	}
+	return goofy.TryGenerate(s, opts)
+}
+
+// goofy_id returns the six-digit ID of s, as goofy.SixDigitID does.
+//
+//export goofy_id
+func goofy_id(s *C.char) *C.char {
+	return C.CString(goofy.SixDigitID(C.GoString(s)))
+}
+
+// goofy_id_opts returns the ID of s under options, which may be NULL for
+// the defaults. On invalid options it returns NULL and, unless errOut is
+// NULL, sets *errOut to the error message.
+//
+//export goofy_id_opts
+func goofy_id_opts(s, options *C.char, errOut **C.char) *C.char {
+	if s == nil {
+		return fail(errOut, errors.New("input is NULL"))
+	}
+	var data string
+	if options != nil {
+		data = C.GoString(options)
+	}
+	id, err := generate(C.GoString(s), data)
+	if err != nil {
+		return fail(errOut, err)
+	}
+	return C.CString(id)
+}
+
+// fail reports err through errOut, if not NULL, and returns NULL.
+func fail(errOut **C.char, err error) *C.char {
+	if errOut != nil {
+		*errOut = C.CString(err.Error())
+	}
+	return nil
+}
+
+// goofy_free releases a string returned by the library.
+//
+//export goofy_free
+func goofy_free(p *C.char) {
+	C.free(unsafe.Pointer(p))
+}
+
+func main() {} // required by -buildmode=c-shared
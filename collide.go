@@ -0,0 +1,359 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// collideCommand defines the flags of "goofy collide" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func collideCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	across := fs.Bool("across", false, "only report pairs whose inputs come from different files")
+	mem := fs.Int("mem", 256<<20, "sort in memory up to about `BYTES`, then spill sorted runs to -tmpdir")
+	tmpdir := fs.String("tmpdir", "", "write sorted runs to `DIR` (default the system temporary directory)")
+	nul := fs.Bool("0", false, "read NUL-separated records instead of lines")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s collide [options] FILE...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Report every pair of distinct inputs, within or across the files, that\n")
+		fmt.Fprintf(os.Stderr, "share an ID, as \"ID<TAB>FILE:LINE<TAB>INPUT<TAB>FILE:LINE<TAB>INPUT\"\n")
+		fmt.Fprintf(os.Stderr, "lines ordered by ID. The inputs are sorted by ID in memory up to -mem\n")
+		fmt.Fprintf(os.Stderr, "and externally beyond, so files of any size can be compared. A FILE of\n")
+		fmt.Fprintf(os.Stderr, "\"-\" reads stdin.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s collide customers.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s collide -across -digits 8 eu.txt us.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - no collisions\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed input\n")
+		fmt.Fprintf(os.Stderr, "  3 - collisions found\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O error\n")
+	}
+
+	return func() int {
+		if fs.NArg() < 1 {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument FILE\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err == nil && *mem < 1<<20 {
+			err = fmt.Errorf("-mem must be at least 1 MiB, got %d", *mem)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		opts.Spaced = false
+		opts.NATO = false
+
+		s := &idSorter{mem: *mem, dir: *tmpdir}
+		defer s.cleanup()
+		in := inputOptions{nul: *nul, maxRecord: defaultMaxRecord}
+		for i, name := range fs.Args() {
+			line := 0
+			err := processFile(name, in, func(input string) error {
+				line++
+				return s.add(idEntry{id: goofy.Generate(input, opts), input: input, file: i, line: line})
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		r := &collisionReporter{out: out, files: fs.Args(), across: *across}
+		err = s.each(r.add)
+		if err == nil {
+			err = r.flush()
+		}
+		if ferr := out.Flush(); err == nil {
+			err = ferr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(ioError{err})
+		}
+		slog.Info("compared", "inputs", s.n, "runs", len(s.runs), "pairs", r.pairs)
+		if r.pairs > 0 {
+			return 3
+		}
+		return 0
+	}
+}
+
+// idEntry is an input read by goofy collide, with its ID and where it was
+// read.
+type idEntry struct {
+	id    string
+	input string
+	file  int // index of the file among the arguments
+	line  int // record number within the file, from 1
+}
+
+// compareEntries orders entries by ID, then by where they were read.
+func compareEntries(a, b idEntry) int {
+	return cmp.Or(cmp.Compare(a.id, b.id), cmp.Compare(a.file, b.file), cmp.Compare(a.line, b.line))
+}
+
+// idSorter sorts entries by ID, in memory up to mem bytes and beyond that
+// externally: each time the buffer fills up it is sorted and spilled to a
+// run file, and the runs are merged in the end.
+type idSorter struct {
+	mem  int
+	dir  string
+	buf  []idEntry
+	used int      // approximate bytes held by buf
+	runs []string // sorted run files
+	n    int      // entries added
+}
+
+// entryOverhead approximates the bytes an entry takes beyond its strings.
+const entryOverhead = 64
+
+func (s *idSorter) add(e idEntry) error {
+	s.buf = append(s.buf, e)
+	s.used += len(e.id) + len(e.input) + entryOverhead
+	s.n++
+	if s.used >= s.mem {
+		return s.spill()
+	}
+	return nil
+}
+
+// spill sorts the buffer and writes it out as a new run.
+func (s *idSorter) spill() error {
+	slices.SortFunc(s.buf, compareEntries)
+	f, err := os.CreateTemp(s.dir, "goofy-collide-*.run")
+	if err != nil {
+		return err
+	}
+	s.runs = append(s.runs, f.Name())
+	w := bufio.NewWriter(f)
+	for _, e := range s.buf {
+		writeEntry(w, e)
+	}
+	err = w.Flush()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	clear(s.buf)
+	s.buf = s.buf[:0]
+	s.used = 0
+	return err
+}
+
+// each calls fn for every entry in sorted order, stopping at the first
+// error.
+func (s *idSorter) each(fn func(idEntry) error) error {
+	if len(s.runs) == 0 {
+		slices.SortFunc(s.buf, compareEntries)
+		for _, e := range s.buf {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if len(s.buf) > 0 {
+		if err := s.spill(); err != nil {
+			return err
+		}
+	}
+	h := make(runHeap, 0, len(s.runs))
+	for _, name := range s.runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r := &runReader{r: bufio.NewReader(f)}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			h = append(h, r)
+		}
+	}
+	heap.Init(&h)
+	for len(h) > 0 {
+		r := h[0]
+		if err := fn(r.cur); err != nil {
+			return err
+		}
+		ok, err := r.next()
+		if err != nil {
+			return err
+		}
+		if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	return nil
+}
+
+// cleanup removes the run files.
+func (s *idSorter) cleanup() {
+	for _, name := range s.runs {
+		os.Remove(name)
+	}
+}
+
+// writeEntry encodes e onto a run: its strings length-prefixed, so that
+// inputs may hold any bytes, and its numbers as uvarints.
+func writeEntry(w *bufio.Writer, e idEntry) {
+	var b [binary.MaxVarintLen64]byte
+	for _, s := range []string{e.id, e.input} {
+		w.Write(b[:binary.PutUvarint(b[:], uint64(len(s)))])
+		w.WriteString(s)
+	}
+	w.Write(b[:binary.PutUvarint(b[:], uint64(e.file))])
+	w.Write(b[:binary.PutUvarint(b[:], uint64(e.line))])
+}
+
+// runReader reads back the entries of a run written by writeEntry.
+type runReader struct {
+	r   *bufio.Reader
+	cur idEntry
+}
+
+// next reads the next entry into r.cur and reports whether there was one.
+func (r *runReader) next() (bool, error) {
+	id, err := r.string()
+	if errors.Is(err, io.EOF) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	input, err := r.string()
+	if err != nil {
+		return false, noEOF(err)
+	}
+	file, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return false, noEOF(err)
+	}
+	line, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return false, noEOF(err)
+	}
+	r.cur = idEntry{id: id, input: input, file: int(file), line: int(line)}
+	return true, nil
+}
+
+func (r *runReader) string() (string, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return "", noEOF(err)
+	}
+	return string(b), nil
+}
+
+// noEOF turns an end of file in the middle of an entry into
+// io.ErrUnexpectedEOF.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// runHeap merges runs by their current entries.
+type runHeap []*runReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return compareEntries(h[i].cur, h[j].cur) < 0 }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
+
+// collisionReporter writes the colliding pairs among entries passed to
+// add in ID order.
+type collisionReporter struct {
+	out    *bufio.Writer
+	files  []string
+	across bool
+	group  []idEntry // distinct inputs of the current ID, first occurrence per file
+	pairs  int
+}
+
+func (c *collisionReporter) add(e idEntry) error {
+	if len(c.group) > 0 && c.group[0].id != e.id {
+		if err := c.flush(); err != nil {
+			return err
+		}
+	}
+	for _, g := range c.group {
+		if g.input == e.input && g.file == e.file {
+			return nil
+		}
+	}
+	c.group = append(c.group, e)
+	return nil
+}
+
+// flush writes the pairs of the current group and starts a new one.
+func (c *collisionReporter) flush() error {
+	for i, a := range c.group {
+		for _, b := range c.group[i+1:] {
+			if a.input == b.input || c.across && a.file == b.file {
+				continue
+			}
+			c.pairs++
+			if _, err := fmt.Fprintf(c.out, "%s\t%s:%d\t%s\t%s:%d\t%s\n",
+				a.id, c.files[a.file], a.line, a.input, c.files[b.file], b.line, b.input); err != nil {
+				return err
+			}
+		}
+	}
+	c.group = c.group[:0]
+	return nil
+}
@@ -0,0 +1,76 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import "github.com/al-maisan/goofy/pkg/goofy"
+
+// collisionDetector remembers the first input seen for every ID and
+// counts distinct inputs that map to the same ID.
+type collisionDetector struct {
+	seen  map[string]string // ID -> first input that produced it
+	count int               // number of collisions reported
+}
+
+func newCollisionDetector() *collisionDetector {
+	return &collisionDetector{seen: make(map[string]string)}
+}
+
+// check records that input produced id and, if a different input
+// produced the same ID before, counts the collision and returns that
+// input. Repeated inputs are not collisions.
+func (d *collisionDetector) check(input, id string) (first string, collided bool) {
+	first, ok := d.seen[id]
+	if !ok {
+		d.seen[id] = input
+		return "", false
+	}
+	if first == input {
+		return "", false
+	}
+	d.count++
+	return first, true
+}
+
+// deduper drops the records of inputs seen before, for -dedupe.
+type deduper struct {
+	byID    bool // inputs are the same if their IDs are
+	seen    map[string]struct{}
+	dropped int
+	skip    bool // the alternatives of the current input are dropped
+}
+
+// drop reports whether rec, generated under opts, belongs to an input
+// seen before. Records must be passed in order, each input's
+// alternatives after its regular ID.
+func (d *deduper) drop(rec record, opts goofy.Options) bool {
+	if rec.Counter != nil && *rec.Counter > 0 {
+		return d.skip
+	}
+	key := rec.ID
+	if !d.byID {
+		key = goofy.Preprocess(rec.Input, opts)
+	}
+	_, d.skip = d.seen[key]
+	if d.skip {
+		d.dropped++
+	} else {
+		d.seen[key] = struct{}{}
+	}
+	return d.skip
+}
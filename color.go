@@ -0,0 +1,50 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// colorModes are the values accepted by -color.
+var colorModes = []string{"auto", "always", "never"}
+
+// ANSI escape sequences used to color text output.
+const (
+	ansiID    = "\x1b[1;36m" // bold cyan
+	ansiInput = "\x1b[2m"    // dim
+	ansiReset = "\x1b[0m"
+)
+
+// useColor reports whether -color mode selects colored output on f. In
+// auto mode that is when f is a terminal and $NO_COLOR is unset or empty.
+func useColor(mode string, f *os.File) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		return os.Getenv("NO_COLOR") == "" && isTerminal(f), nil
+	default:
+		return false, fmt.Errorf("unknown -color mode %q (want %s)", mode, strings.Join(colorModes, ", "))
+	}
+}
@@ -0,0 +1,241 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// completionShells maps shells to their completion script writers.
+var completionShells = map[string]func(w io.Writer, specs []commandSpec){
+	"bash": writeBashCompletion,
+	"zsh":  writeZshCompletion,
+	"fish": writeFishCompletion,
+}
+
+// completionCommand defines the flags of "goofy completion" on fs and
+// returns the function running it once they are parsed, which returns the
+// process exit code.
+func completionCommand(fs *flag.FlagSet) func() int {
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s completion bash|zsh|fish\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print a shell completion script for goofy's subcommands and flags.\n")
+		fmt.Fprintf(os.Stderr, "The script is generated from the flag definitions of this binary.\n\n")
+		fmt.Fprintf(os.Stderr, "Examples:\n")
+		fmt.Fprintf(os.Stderr, "  source <(%s completion bash)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s completion zsh > \"${fpath[1]}/_goofy\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s completion fish > ~/.config/fish/completions/goofy.fish\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one shell\n\n")
+			fs.Usage()
+			return 1
+		}
+		write, ok := completionShells[fs.Arg(0)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unsupported shell %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+		w := bufio.NewWriter(os.Stdout)
+		write(w, commandSpecs())
+		if err := w.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+}
+
+// commandSpec describes a command's flags for completion.
+type commandSpec struct {
+	name    string // empty for goofy itself
+	summary string
+	flags   []flagSpec
+}
+
+// flagSpec describes a single flag for completion.
+type flagSpec struct {
+	name  string
+	arg   string // name of the flag's argument; empty for boolean flags
+	usage string
+}
+
+// commandSpecs returns the specs of goofy itself and all subcommands, by
+// setting each up on a scratch flag set.
+func commandSpecs() []commandSpec {
+	specs := []commandSpec{{flags: flagSpecs(rootCommand)}}
+	for _, c := range commands {
+		specs = append(specs, commandSpec{name: c.name, summary: c.summary, flags: flagSpecs(c.setup)})
+	}
+	return specs
+}
+
+// flagSpecs returns the flags defined by setup, sorted by name.
+func flagSpecs(setup func(fs *flag.FlagSet) func() int) []flagSpec {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	addLogFlags(fs)
+	setup(fs)
+	var specs []flagSpec
+	fs.VisitAll(func(f *flag.Flag) {
+		arg, usage := flag.UnquoteUsage(f)
+		if b, ok := f.Value.(interface{ IsBoolFlag() bool }); ok && b.IsBoolFlag() {
+			arg = ""
+		}
+		specs = append(specs, flagSpec{name: f.Name, arg: arg, usage: usage})
+	})
+	return specs
+}
+
+// takesFile reports whether the argument of f names a file.
+func (f flagSpec) takesFile() bool {
+	return f.arg == "FILE" || f.arg == "PATH"
+}
+
+func writeBashCompletion(w io.Writer, specs []commandSpec) {
+	var names []string
+	for _, s := range specs[1:] {
+		names = append(names, s.name)
+	}
+	fmt.Fprintf(w, "# bash completion for goofy; generated by \"goofy completion bash\"\n")
+	fmt.Fprintf(w, "_goofy() {\n")
+	fmt.Fprintf(w, "\tlocal cur=${COMP_WORDS[COMP_CWORD]} cmd= words=\n")
+	fmt.Fprintf(w, "\t[[ $COMP_CWORD -gt 1 ]] && cmd=${COMP_WORDS[1]}\n")
+	fmt.Fprintf(w, "\tcase $cmd in\n")
+	for _, s := range specs[1:] {
+		fmt.Fprintf(w, "\t%s) words=%q ;;\n", s.name, flagWords(s.flags))
+	}
+	fmt.Fprintf(w, "\t*)\n")
+	fmt.Fprintf(w, "\t\twords=%q\n", flagWords(specs[0].flags))
+	fmt.Fprintf(w, "\t\t[[ $COMP_CWORD -eq 1 ]] && words+=%q\n", " "+strings.Join(names, " "))
+	fmt.Fprintf(w, "\t\t;;\n")
+	fmt.Fprintf(w, "\tesac\n")
+	fmt.Fprintf(w, "\tif [[ $cur == -* || $COMP_CWORD -eq 1 ]]; then\n")
+	fmt.Fprintf(w, "\t\tCOMPREPLY=($(compgen -W \"$words\" -- \"$cur\"))\n")
+	fmt.Fprintf(w, "\tfi\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "complete -o default -F _goofy goofy\n")
+}
+
+// flagWords returns the flags as a space-separated word list.
+func flagWords(flags []flagSpec) string {
+	words := make([]string, len(flags))
+	for i, f := range flags {
+		words[i] = "-" + f.name
+	}
+	return strings.Join(words, " ")
+}
+
+func writeZshCompletion(w io.Writer, specs []commandSpec) {
+	fmt.Fprintf(w, "#compdef goofy\n")
+	fmt.Fprintf(w, "# zsh completion for goofy; generated by \"goofy completion zsh\"\n")
+	fmt.Fprintf(w, "_goofy() {\n")
+	fmt.Fprintf(w, "\tif (( CURRENT == 2 )); then\n")
+	fmt.Fprintf(w, "\t\tlocal -a cmds=(\n")
+	for _, s := range specs[1:] {
+		fmt.Fprintf(w, "\t\t\t%s\n", shellQuote(s.name+":"+zshEscape(s.summary)))
+	}
+	fmt.Fprintf(w, "\t\t)\n")
+	fmt.Fprintf(w, "\t\t_describe -t commands 'goofy command' cmds\n")
+	fmt.Fprintf(w, "\tfi\n")
+	fmt.Fprintf(w, "\tcase $words[2] in\n")
+	for _, s := range specs[1:] {
+		fmt.Fprintf(w, "\t%s)\n", s.name)
+		fmt.Fprintf(w, "\t\tshift words\n")
+		fmt.Fprintf(w, "\t\t(( CURRENT-- ))\n")
+		writeZshArguments(w, s.flags)
+		fmt.Fprintf(w, "\t\t;;\n")
+	}
+	fmt.Fprintf(w, "\t*)\n")
+	writeZshArguments(w, specs[0].flags)
+	fmt.Fprintf(w, "\t\t;;\n")
+	fmt.Fprintf(w, "\tesac\n")
+	fmt.Fprintf(w, "}\n")
+	fmt.Fprintf(w, "_goofy \"$@\"\n")
+}
+
+// writeZshArguments writes an _arguments call completing flags.
+func writeZshArguments(w io.Writer, flags []flagSpec) {
+	fmt.Fprintf(w, "\t\t_arguments \\\n")
+	for _, f := range flags {
+		spec := "-" + f.name + "[" + zshEscape(f.usage) + "]"
+		if f.arg != "" {
+			action := " "
+			if f.takesFile() {
+				action = "_files"
+			}
+			spec += ":" + zshEscape(f.arg) + ":" + action
+		}
+		fmt.Fprintf(w, "\t\t\t%s \\\n", shellQuote(spec))
+	}
+	fmt.Fprintf(w, "\t\t\t'*:file:_files'\n")
+}
+
+// zshEscape escapes the characters special in _arguments and _describe
+// specs.
+var zshEscape = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`, `:`, `\:`).Replace
+
+// shellQuote single-quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func writeFishCompletion(w io.Writer, specs []commandSpec) {
+	var names []string
+	for _, s := range specs[1:] {
+		names = append(names, s.name)
+	}
+	root := "not __fish_seen_subcommand_from " + strings.Join(names, " ")
+	fmt.Fprintf(w, "# fish completion for goofy; generated by \"goofy completion fish\"\n")
+	for _, s := range specs[1:] {
+		fmt.Fprintf(w, "complete -c goofy -n %s -f -a %s -d %s\n", fishQuote(root), s.name, fishQuote(s.summary))
+	}
+	writeFishFlags(w, root, specs[0].flags)
+	for _, s := range specs[1:] {
+		writeFishFlags(w, "__fish_seen_subcommand_from "+s.name, s.flags)
+	}
+}
+
+// writeFishFlags writes complete commands for flags, active under cond.
+func writeFishFlags(w io.Writer, cond string, flags []flagSpec) {
+	for _, f := range flags {
+		fmt.Fprintf(w, "complete -c goofy -n %s -o %s", fishQuote(cond), f.name)
+		if f.arg != "" {
+			if f.takesFile() {
+				fmt.Fprintf(w, " -r -F")
+			} else {
+				fmt.Fprintf(w, " -x")
+			}
+		}
+		fmt.Fprintf(w, " -d %s\n", fishQuote(f.usage))
+	}
+}
+
+// fishQuote single-quotes s for fish, which unlike POSIX shells allows
+// escaping quotes and backslashes within single quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,225 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// envName returns the environment variable providing a value for the
+// named flag, e.g. GOOFY_MAX_BYTES for -max-bytes.
+func envName(flag string) string {
+	return "GOOFY_" + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// lookupEnv returns the value the environment provides for the named
+// flag, and the variable it came from.
+func lookupEnv(flag string) (val, from string, ok bool) {
+	if flag == "h" {
+		return "", "", false
+	}
+	if val, ok := os.LookupEnv(envName(flag)); ok {
+		return val, envName(flag), true
+	}
+	return "", "", false
+}
+
+// configPath returns the path of the configuration file: $GOOFY_CONFIG if
+// set, and goofy/config.toml in the user's configuration directory
+// (usually ~/.config) otherwise. explicit reports the former.
+func configPath() (path string, explicit bool, err error) {
+	if path := os.Getenv("GOOFY_CONFIG"); path != "" {
+		return path, true, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false, err
+	}
+	return filepath.Join(dir, "goofy", "config.toml"), false, nil
+}
+
+// config holds the flag defaults read from the configuration file:
+// top-level keys apply to every command that has a flag of that name,
+// tables named after a subcommand only to that subcommand, e.g.
+//
+//	algo = "sha256"
+//	digits = 8
+//
+//	[serve]
+//	listen = ":8080"
+type config struct {
+	path     string
+	global   map[string]any
+	commands map[string]map[string]any
+}
+
+// loadConfig reads and checks the configuration file. A missing file
+// yields an empty configuration unless it was named by $GOOFY_CONFIG.
+func loadConfig() (*config, error) {
+	path, explicit, err := configPath()
+	if err != nil {
+		return &config{}, nil // no home directory, so no configuration
+	}
+	var raw map[string]any
+	if _, err := toml.DecodeFile(path, &raw); err != nil {
+		if errors.Is(err, os.ErrNotExist) && !explicit {
+			return &config{}, nil
+		}
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	specs := commandSpecs()
+	known := make(map[string]bool)
+	for _, s := range specs {
+		for _, f := range s.flags {
+			known[f.name] = true
+		}
+	}
+	c := &config{path: path, global: make(map[string]any), commands: make(map[string]map[string]any)}
+	for _, key := range sortedKeys(raw) {
+		if table, ok := raw[key].(map[string]any); ok {
+			spec, ok := findSpec(specs, key)
+			if !ok || key == "" {
+				return nil, fmt.Errorf("config %s: unknown command [%s]", path, key)
+			}
+			for _, name := range sortedKeys(table) {
+				if !spec.hasFlag(name) {
+					return nil, fmt.Errorf("config %s: command %s has no flag -%s", path, key, name)
+				}
+			}
+			c.commands[key] = table
+			continue
+		}
+		if !known[key] {
+			return nil, fmt.Errorf("config %s: unknown flag %q", path, key)
+		}
+		c.global[key] = raw[key]
+	}
+	return c, nil
+}
+
+// lookup returns the configured value of the named flag of cmd.
+func (c *config) lookup(cmd, name string) (string, bool) {
+	if v, ok := c.commands[cmd][name]; ok {
+		return fmt.Sprint(v), true
+	}
+	if v, ok := c.global[name]; ok {
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// exclusiveFlags lists pairs of flags that cannot be combined. Either of
+// a pair set on the command line keeps applyDefaults from setting the
+// other, so that e.g. -qr overrides output = "json" from the
+// configuration file instead of clashing with it.
+var exclusiveFlags = [][2]string{
+	{"alphabet", "base"},
+	{"entropy", "digits"},
+	{"entropy", "words"},
+	{"format", "qr"},
+	{"format", "output"},
+	{"format", "echo"},
+	{"qr", "output"},
+	{"qr", "0"},
+}
+
+// defaulted records the flags applyDefaults set, which isSet counts but
+// onCommandLine does not.
+var defaulted = make(map[*flag.Flag]bool)
+
+// applyDefaults gives every flag of cmd that was not set on the command
+// line its value from the environment (see envName) or, failing that,
+// from the configuration file, unless a flag excluding it was set on the
+// command line (see exclusiveFlags). Flags set this way count as set for
+// isSet, so the precedence is configuration < environment < flags.
+func applyDefaults(fs *flag.FlagSet, cmd string) error {
+	c, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if isSet(fs, f.Name) || excludedOnCommandLine(fs, f.Name) {
+			return
+		}
+		if val, from, ok := lookupEnv(f.Name); ok {
+			if err := fs.Set(f.Name, val); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q for -%s from $%s: %w", val, f.Name, from, err))
+			}
+			defaulted[f] = true
+			return
+		}
+		if val, ok := c.lookup(cmd, f.Name); ok {
+			if err := fs.Set(f.Name, val); err != nil {
+				errs = append(errs, fmt.Errorf("config %s: invalid value %q for -%s: %w", c.path, val, f.Name, err))
+			}
+			defaulted[f] = true
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// excludedOnCommandLine reports whether a flag that cannot be combined
+// with the named one was set on the command line.
+func excludedOnCommandLine(fs *flag.FlagSet, name string) bool {
+	for _, pair := range exclusiveFlags {
+		if pair[0] == name && onCommandLine(fs, pair[1]) || pair[1] == name && onCommandLine(fs, pair[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// findSpec returns the spec of the named subcommand.
+func findSpec(specs []commandSpec, name string) (commandSpec, bool) {
+	for _, s := range specs {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return commandSpec{}, false
+}
+
+// hasFlag reports whether the command has the named flag.
+func (s commandSpec) hasFlag(name string) bool {
+	for _, f := range s.flags {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyDefaults(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(config, []byte("output = \"json\"\ndigits = 8\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GOOFY_CONFIG", config)
+	t.Setenv("GOOFY_ROTATE", "daily")
+
+	tests := []struct {
+		args        []string
+		want        map[string]string // flag values after applyDefaults
+		commandLine []string          // flags onCommandLine reports
+	}{
+		{
+			args: nil,
+			want: map[string]string{"output": "json", "digits": "8", "rotate": "daily", "qr": ""},
+		},
+		{
+			args:        []string{"-digits", "10", "-rotate", "weekly"},
+			want:        map[string]string{"output": "json", "digits": "10", "rotate": "weekly"},
+			commandLine: []string{"digits", "rotate"},
+		},
+		{
+			// -qr and -format exclude -output, which is left at its default.
+			args:        []string{"-qr", "-"},
+			want:        map[string]string{"output": "text", "qr": "-", "digits": "8"},
+			commandLine: []string{"qr"},
+		},
+		{
+			args:        []string{"-format", "{{.ID}}"},
+			want:        map[string]string{"output": "text", "format": "{{.ID}}"},
+			commandLine: []string{"format"},
+		},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+		addGenFlags(fs)
+		addOutputFlags(fs, true)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+		if err := applyDefaults(fs, "gen"); err != nil {
+			t.Fatalf("%q: %v", tt.args, err)
+		}
+		for name, want := range tt.want {
+			if got := fs.Lookup(name).Value.String(); got != want {
+				t.Errorf("%q: -%s = %q, want %q", tt.args, name, got, want)
+			}
+		}
+		onLine := make(map[string]bool)
+		for _, name := range tt.commandLine {
+			onLine[name] = true
+		}
+		fs.VisitAll(func(f *flag.Flag) {
+			if got := onCommandLine(fs, f.Name); got != onLine[f.Name] {
+				t.Errorf("%q: onCommandLine(-%s) = %v, want %v", tt.args, f.Name, got, onLine[f.Name])
+			}
+		})
+	}
+}
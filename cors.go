@@ -0,0 +1,103 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight.
+const corsMaxAge = "600"
+
+// corsPolicy is the cross-origin policy of the HTTP API (-cors-origins).
+type corsPolicy struct {
+	origins []string // allowed origins, or "*" for any
+	methods string   // Access-Control-Allow-Methods
+	headers string   // Access-Control-Allow-Headers
+}
+
+// newCORSPolicy returns the policy allowing the comma-separated origins,
+// methods and request headers, or nil if origins is empty.
+func newCORSPolicy(origins, methods, headers string) *corsPolicy {
+	if origins == "" {
+		return nil
+	}
+	return &corsPolicy{
+		origins: splitList(origins),
+		methods: strings.Join(splitList(methods), ", "),
+		headers: strings.Join(splitList(headers), ", "),
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping blanks.
+func splitList(s string) []string {
+	var items []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin,
+// or "" if origin may not call the API.
+func (c *corsPolicy) allowOrigin(origin string) string {
+	switch {
+	case slices.Contains(c.origins, "*"):
+		return "*"
+	case slices.Contains(c.origins, origin):
+		return origin
+	}
+	return ""
+}
+
+// handler wraps h, adding CORS headers to responses for allowed origins
+// and answering their preflight requests itself, before API keys are
+// checked: browsers never send credentials with a preflight.
+func (c *corsPolicy) handler(h http.Handler) http.Handler {
+	if c == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		allowed := c.allowOrigin(origin)
+		if allowed == "" {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", allowed)
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", c.methods)
+			w.Header().Set("Access-Control-Allow-Headers", c.headers)
+			w.Header().Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join([]string{requestIDHeader, "Retry-After"}, ", "))
+		h.ServeHTTP(w, r)
+	})
+}
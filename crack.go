@@ -0,0 +1,153 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// crackCommand defines the flags of "goofy crack" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func crackCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	wordlist := fs.String("wordlist", "", "read candidate inputs from `FILE`, one per line (\"-\" for stdin; required)")
+	mutate := fs.Bool("mutate", false, "also try each candidate lowercased, uppercased, capitalized and followed by 0-99 or !")
+	nul := fs.Bool("0", false, "read NUL-separated candidates instead of lines")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s crack [options] -wordlist FILE <ID>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Hash every candidate of a wordlist and print those whose ID is one of the\n")
+		fmt.Fprintf(os.Stderr, "given IDs, as \"ID<TAB>input\" lines, to recover what an ID referred to\n")
+		fmt.Fprintf(os.Stderr, "without a registry. IDs are short, so expect unrelated candidates to\n")
+		fmt.Fprintf(os.Stderr, "match too: a 6-digit ID matches one in a million candidates by chance.\n")
+		fmt.Fprintf(os.Stderr, "The generation options must be those the IDs were made with.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s crack -wordlist /usr/share/dict/words 259144\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s crack -wordlist names.txt -mutate -salt prod \"46 95 00\" 118622\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - at least one candidate matched\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed input\n")
+		fmt.Fprintf(os.Stderr, "  2 - no candidate matched\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O error\n")
+	}
+
+	return func() int {
+		if fs.NArg() < 1 {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument <ID>\n\n")
+			fs.Usage()
+			return 1
+		}
+		if *wordlist == "" {
+			fmt.Fprintf(os.Stderr, "Error: -wordlist is required\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		opts.Spaced = false
+		opts.NATO = false
+		targets := make(map[string]bool, fs.NArg())
+		for _, id := range fs.Args() {
+			id = strings.ReplaceAll(id, " ", "")
+			if opts.Sep != "" {
+				id = strings.ReplaceAll(id, opts.Sep, "")
+			}
+			targets[id] = true
+		}
+
+		out := bufio.NewWriter(os.Stdout)
+		found := make(map[string]bool)
+		try := func(candidate string) {
+			id := goofy.Generate(candidate, opts)
+			if targets[id] && !found[candidate] {
+				found[candidate] = true
+				fmt.Fprintf(out, "%s\t%s\n", id, candidate)
+			}
+		}
+		err = processFile(*wordlist, inputOptions{nul: *nul}, func(word string) error {
+			if *mutate {
+				for _, candidate := range mutations(word) {
+					try(candidate)
+				}
+			} else {
+				try(word)
+			}
+			return nil
+		})
+		if ferr := out.Flush(); err == nil {
+			err = ferr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		if len(found) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no candidate matched\n")
+			return 2
+		}
+		return 0
+	}
+}
+
+// mutations returns word and its -mutate variants, without duplicates:
+// lowercased, uppercased and capitalized, and each of these followed by
+// a number from 0 to 99 or by "!".
+func mutations(word string) []string {
+	var bases []string
+	for _, w := range []string{word, strings.ToLower(word), strings.ToUpper(word), capitalize(word)} {
+		if !slices.Contains(bases, w) {
+			bases = append(bases, w)
+		}
+	}
+	out := make([]string, 0, len(bases)*102)
+	for _, b := range bases {
+		out = append(out, b, b+"!")
+		for n := range 100 {
+			out = append(out, b+strconv.Itoa(n))
+		}
+	}
+	return out
+}
+
+// capitalize returns s with its first letter uppercased and the rest
+// lowercased.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + strings.ToLower(s[size:])
+}
@@ -0,0 +1,164 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// csvCommand defines the flags of "goofy csv" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func csvCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	in := fs.String("in", "-", "read the CSV from `FILE` (\"-\" for stdin)")
+	out := fs.String("out", "-", "write the CSV to `FILE` (\"-\" for stdout)")
+	column := fs.String("column", "", "generate IDs for the values of the column headed `NAME`")
+	idColumn := fs.String("id-column", "", "head the added ID column `NAME` (default the -column name with an _id suffix)")
+	comma := fs.String("comma", ",", "field separator `CHAR`, e.g. \";\" or \"\\t\"")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s csv [options] -column NAME\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Copy a CSV file with a header row, adding a column with the plain ID of\n")
+		fmt.Fprintf(os.Stderr, "the value in -column to every row. Rows are streamed, so files of any\n")
+		fmt.Fprintf(os.Stderr, "size are fine; quoting is preserved where needed.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s csv -in data.csv -column email -out data_out.csv\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s csv -column email -id-column code -key \"$SECRET\" < data.csv\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		sep, size := utf8.DecodeRuneInString(*comma)
+		switch {
+		case err != nil:
+		case *column == "":
+			err = fmt.Errorf("missing required -column NAME")
+		case *comma == `\t`:
+			sep = '\t'
+		case size != len(*comma) || sep == utf8.RuneError:
+			err = fmt.Errorf("-comma must be a single character, got %q", *comma)
+		case *in != "-" && *in == *out:
+			err = fmt.Errorf("-in and -out must not be the same file")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		name := *idColumn
+		if name == "" {
+			name = *column + "_id"
+		}
+
+		r, w := io.Reader(os.Stdin), io.Writer(os.Stdout)
+		if *in != "-" {
+			f, err := os.Open(*in)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+			defer f.Close()
+			r = f
+		}
+		var outFile *os.File
+		if *out != "-" {
+			if outFile, err = os.Create(*out); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+			w = outFile
+		}
+
+		err = transformCSV(r, w, sep, *column, name, opts)
+		if outFile != nil {
+			if cerr := outFile.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+}
+
+// transformCSV copies the CSV in r to w, appending a column headed name
+// with the ID under opts of each row's value in column.
+func transformCSV(r io.Reader, w io.Writer, sep rune, column, name string, opts goofy.Options) error {
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.ReuseRecord = true
+	cw := csv.NewWriter(w)
+	cw.Comma = sep
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("missing header row")
+	}
+	if err != nil {
+		return err
+	}
+	col := slices.Index(header, column)
+	if col < 0 {
+		return fmt.Errorf("no column %q in header %q", column, header)
+	}
+	if slices.Contains(header, name) {
+		return fmt.Errorf("column %q already exists, pick another -id-column", name)
+	}
+	if err := cw.Write(append(header, name)); err != nil {
+		return err
+	}
+
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		id, err := goofy.TryGenerate(row[col], opts)
+		if err != nil {
+			return err
+		}
+		if err := cw.Write(append(row, id)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,166 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// idSet is the set of distinct inputs read from a file and their IDs.
+type idSet struct {
+	inputs []string          // in the order first read
+	ids    map[string]string // input -> ID
+}
+
+// readIDSet reads the records of file and generates their IDs under opts.
+func readIDSet(file string, in inputOptions, opts goofy.Options) (*idSet, error) {
+	s := &idSet{ids: make(map[string]string)}
+	err := processFile(file, in, func(input string) error {
+		if _, ok := s.ids[input]; !ok {
+			s.inputs = append(s.inputs, input)
+			s.ids[input] = goofy.Generate(input, opts)
+		}
+		return nil
+	})
+	return s, err
+}
+
+// diffCommand defines the flags of "goofy diff" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func diffCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	plain := fs.Bool("plain", false, "output as plain 6-digit string")
+	nul := fs.Bool("0", false, "read NUL-separated records instead of lines")
+	maxRecord := fs.Int("max-record", defaultMaxRecord, "fail cleanly on input records longer than `N` bytes")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s diff [options] OLD NEW\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Compare the IDs of the records of two files (\"-\" for stdin), e.g. two\n")
+		fmt.Fprintf(os.Stderr, "versions of a dataset, and list the differences, one per line:\n\n")
+		fmt.Fprintf(os.Stderr, "  - ID<TAB>INPUT           INPUT of OLD is missing from NEW\n")
+		fmt.Fprintf(os.Stderr, "  + ID<TAB>INPUT           INPUT of NEW is missing from OLD\n")
+		fmt.Fprintf(os.Stderr, "  ! ID<TAB>INPUT<TAB>...   distinct inputs share an ID of NEW, within NEW\n")
+		fmt.Fprintf(os.Stderr, "                           or because a removed input had it in OLD\n\n")
+		fmt.Fprintf(os.Stderr, "Removed inputs are listed in the order of OLD, then added ones in the\n")
+		fmt.Fprintf(os.Stderr, "order of NEW, then colliding IDs in sorted order. A summary goes to\n")
+		fmt.Fprintf(os.Stderr, "stderr.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s diff customers-2024.txt customers-2025.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - same inputs\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed input\n")
+		fmt.Fprintf(os.Stderr, "  2 - inputs added or removed\n")
+		fmt.Fprintf(os.Stderr, "  3 - IDs of NEW shared by distinct inputs\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O error\n")
+	}
+
+	return func() int {
+		if fs.NArg() != 2 {
+			fmt.Fprintf(os.Stderr, "Error: expected OLD and NEW\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		opts.Spaced = !*plain
+
+		in := inputOptions{nul: *nul, maxRecord: *maxRecord}
+		old, err := readIDSet(fs.Arg(0), in, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		cur, err := readIDSet(fs.Arg(1), in, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+
+		w := bufio.NewWriter(os.Stdout)
+		removed := writeMissing(w, '-', old, cur)
+		added := writeMissing(w, '+', cur, old)
+		colliding := writeColliding(w, old, cur)
+		if err := w.Flush(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		fmt.Fprintf(os.Stderr, "%d removed, %d added, %d colliding ID(s)\n", removed, added, colliding)
+		switch {
+		case colliding > 0:
+			return 3
+		case removed > 0 || added > 0:
+			return 2
+		}
+		return 0
+	}
+}
+
+// writeMissing writes the inputs of a missing from b, marked with mark,
+// and returns their number.
+func writeMissing(w *bufio.Writer, mark byte, a, b *idSet) int {
+	n := 0
+	for _, input := range a.inputs {
+		if _, ok := b.ids[input]; !ok {
+			fmt.Fprintf(w, "%c %s\t%s\n", mark, a.ids[input], input)
+			n++
+		}
+	}
+	return n
+}
+
+// writeColliding writes the IDs of cur that distinct inputs of cur, or of
+// cur and removed inputs of old, share, and returns their number.
+func writeColliding(w *bufio.Writer, old, cur *idSet) int {
+	owners := make(map[string][]string) // ID -> distinct inputs
+	for _, input := range cur.inputs {
+		id := cur.ids[input]
+		owners[id] = append(owners[id], input)
+	}
+	for _, input := range old.inputs {
+		id := old.ids[input]
+		if _, kept := cur.ids[input]; !kept && len(owners[id]) > 0 {
+			owners[id] = append(owners[id], input)
+		}
+	}
+	var ids []string
+	for id, inputs := range owners {
+		if len(inputs) > 1 {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		fmt.Fprintf(w, "! %s\t%s\n", id, strings.Join(owners[id], "\t"))
+	}
+	return len(ids)
+}
@@ -0,0 +1,141 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// globs is a repeatable flag collecting glob patterns.
+type globs []string
+
+func (g *globs) String() string { return fmt.Sprint(*g) }
+
+func (g *globs) Set(pattern string) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid glob %q: %w", pattern, err)
+	}
+	*g = append(*g, pattern)
+	return nil
+}
+
+// match reports whether any pattern matches the slash-separated path rel
+// or its last element.
+func (g globs) match(rel string) bool {
+	for _, pattern := range g {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, path.Base(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// dirCommand defines the flags of "goofy dir" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func dirCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	out := addOutputFlags(fs, true)
+	content := fs.Bool("content", false, "derive IDs from the content of the files rather than their paths")
+	jobs := fs.Int("jobs", 1, "generate IDs on `N` goroutines, preserving the order of the files")
+	var include, exclude globs
+	fs.Var(&include, "include", "only emit files matching `GLOB` (repeatable)")
+	fs.Var(&exclude, "exclude", "skip files and directories matching `GLOB` (repeatable)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s dir [options] PATH\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Walk the directory tree at PATH in lexical order and emit an ID for\n")
+		fmt.Fprintf(os.Stderr, "every regular file: the ID of its slash-separated path relative to PATH\n")
+		fmt.Fprintf(os.Stderr, "or, with -content, of its content (hashed whole unless -max-bytes is\n")
+		fmt.Fprintf(os.Stderr, "given). Globs match relative paths or just the file name, so *.png\n")
+		fmt.Fprintf(os.Stderr, "matches at any depth; excluded directories are not descended into.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s dir -echo assets/\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s dir -content -include '*.png' -include '*.jpg' -exclude .git -output csv assets/\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one PATH\n\n")
+			fs.Usage()
+			return 1
+		}
+		root := fs.Arg(0)
+		opts, err := gen.options()
+		switch {
+		case err != nil:
+		case *jobs < 1:
+			err = fmt.Errorf("-jobs must be at least 1, got %d", *jobs)
+		case *out.url.enabled:
+			err = errors.New("-url cannot be combined with dir")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		if *content {
+			out.load = func(rel string) (string, error) {
+				data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(rel)))
+				return string(data), err
+			}
+		}
+
+		return out.generate(opts, *jobs, func(emit func(string) error) error {
+			return walkDir(root, include, exclude, emit)
+		})
+	}
+}
+
+// walkDir calls emit with the slash-separated path relative to root of
+// every regular file below root that matches include, if given, and does
+// not match exclude.
+func walkDir(root string, include, exclude globs, emit func(string) error) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if exclude.match(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || len(include) > 0 && !include.match(rel) {
+			return nil
+		}
+		return emit(rel)
+	})
+}
@@ -0,0 +1,296 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// genFlags are the ID generation flags shared by every mode.
+type genFlags struct {
+	fs        *flag.FlagSet
+	algo      *string
+	digits    *int
+	base      *int
+	alphabet  *string
+	entropy   *int
+	words     *int
+	nato      *bool
+	check     *string
+	group     *int
+	normalize *string
+	fold      *bool
+	trim      *bool
+	squash    *bool
+	maxBytes  *int
+	sep       *string
+	key       *string
+	hmacKey   *string
+	namespace *string
+	ranges    *string
+	salt      *string
+	construct *int
+	rotate    *string
+	at        *string
+	// requestNamespaces is set by commands taking the namespace with
+	// each request, which -namespace-ranges then applies to.
+	requestNamespaces bool
+}
+
+// addGenFlags registers the ID generation flags on fs.
+func addGenFlags(fs *flag.FlagSet) *genFlags {
+	return &genFlags{
+		fs:        fs,
+		algo:      fs.String("algo", goofy.DefaultAlgo, "hash `ALGORITHM`: "+strings.Join(goofy.Algorithms(), ", ")+" (xxhash is short for xxhash64), or exec:PATH to ask the helper program at PATH (see the README)"),
+		digits:    fs.Int("digits", goofy.DefaultDigits, fmt.Sprintf("ID length in `N` digits (%d-%d), or symbols with -base", goofy.MinDigits, goofy.MaxDigits)),
+		words:     fs.Int("words", 0, fmt.Sprintf("render IDs as `N` words and a number, e.g. maple-otter-42 (%d-%d)", goofy.MinWords, goofy.MaxWords)),
+		nato:      fs.Bool("nato", false, "spell IDs out as NATO-style callouts, e.g. \"two five niner one four four\""),
+		check:     fs.String("check-digit", "", "append a check digit computed with `SCHEME`: "+strings.Join(goofy.CheckDigitSchemes(), ", ")),
+		group:     fs.Int("group", goofy.DefaultGroup, "group spaced IDs in runs of `N` symbols"),
+		sep:       fs.String("sep", goofy.DefaultSep, "separate groups of spaced IDs with `SEP`, e.g. \"-\" for 259-144 with -group 3"),
+		base:      fs.Int("base", goofy.DefaultBase, fmt.Sprintf("render IDs in base `B`: one of %v", goofy.Bases())),
+		alphabet:  fs.String("alphabet", "", "render IDs with the symbols of `ALPHABET` instead of -base, e.g. ABCDEFGHJKMNPQRSTUVWXYZ23456789 (no confusable characters such as 0 and O)"),
+		entropy:   fs.Int("entropy", 0, "make IDs just long enough to carry `BITS` bits of entropy instead of setting -digits"),
+		normalize: fs.String("normalize", "", "bring inputs into Unicode normalization `FORM` before hashing: "+strings.Join(goofy.Normalizations(), ", ")),
+		fold:      fs.Bool("fold", false, "case fold inputs before hashing, e.g. for case-insensitive email addresses"),
+		trim:      fs.Bool("trim", false, "strip leading and trailing white space from inputs before hashing"),
+		squash:    fs.Bool("squash-spaces", false, "collapse runs of white space in inputs into one space before hashing"),
+		maxBytes:  fs.Int("max-bytes", goofy.MaxBytes, "hash at most the first `N` bytes of each input (0 for unlimited)"),
+		key:       fs.String("key", "", "secret `KEY` for keyed algorithms: hmac-sha256 (selected by default), or siphash with 16 bytes or 32 hex digits (default $GOOFY_KEY)"),
+		hmacKey:   fs.String("hmac-key", "", "alias of -key"),
+		namespace: fs.String("namespace", "", "hash inputs within namespace `NAME`, giving it an ID space independent of other namespaces (and of salts with -construction 2)"),
+		ranges:    fs.String("namespace-ranges", "", "confine the IDs of each -namespace to its own value range, e.g. `eu=0-499999,us=500000-999999`"),
+		salt:      fs.String("salt", "", "mix `SALT` into every hash for a per-deployment ID space (default $GOOFY_SALT)"),
+		construct: fs.Int("construction", 1, fmt.Sprintf("assemble the hashed message with construction `N` (1-%d): 1 joins salt, period, namespace and input with NUL bytes, 2 tags and length-prefixes them so that none can alias another (see the README)", goofy.MaxConstruction)),
+		rotate:    fs.String("rotate", "", "mix the current UTC `PERIOD` into every hash so IDs change with it: "+strings.Join(goofy.Rotations(), ", ")+", or a duration such as 1h"),
+		at:        fs.String("at", "", "take the -rotate period at `DATE` (2006-01-02 or RFC 3339) instead of now"),
+	}
+}
+
+// options returns the validated generator options selected by the flags.
+// A key selects hmac-sha256 unless -algo is set as well. Keys of 32 hex
+// digits for algorithms taking 16-byte keys are decoded.
+func (g *genFlags) options() (goofy.Options, error) {
+	opts := goofy.Options{
+		Digits:       *g.digits,
+		Base:         *g.base,
+		Words:        *g.words,
+		NATO:         *g.nato,
+		CheckDigit:   *g.check,
+		Group:        *g.group,
+		Sep:          *g.sep,
+		Normalize:    *g.normalize,
+		Fold:         *g.fold,
+		Trim:         *g.trim,
+		SquashSpaces: *g.squash,
+		MaxBytes:     *g.maxBytes,
+		Algo:         *g.algo,
+		Salt:         *g.salt,
+		Namespace:    *g.namespace,
+		Construction: *g.construct,
+		Rotate:       *g.rotate,
+	}
+	if opts.Construction < 1 || opts.Construction > goofy.MaxConstruction {
+		return goofy.Options{}, fmt.Errorf("-construction must be between 1 and %d, got %d", goofy.MaxConstruction, opts.Construction)
+	}
+	if opts.Alphabet = *g.alphabet; opts.Alphabet != "" {
+		if onCommandLine(g.fs, "base") {
+			return goofy.Options{}, fmt.Errorf("-alphabet and -base are mutually exclusive")
+		}
+		opts.Base = 0
+	}
+	if isSet(g.fs, "entropy") {
+		if onCommandLine(g.fs, "digits") || onCommandLine(g.fs, "words") {
+			return goofy.Options{}, fmt.Errorf("-entropy cannot be combined with -digits or -words")
+		}
+		if *g.entropy < 1 {
+			return goofy.Options{}, fmt.Errorf("-entropy must be positive, got %d", *g.entropy)
+		}
+		opts.Digits = opts.DigitsFor(*g.entropy)
+		if opts.Digits > goofy.MaxDigits {
+			return goofy.Options{}, fmt.Errorf("-entropy %d needs %d symbols, more than the maximum of %d", *g.entropy, opts.Digits, goofy.MaxDigits)
+		}
+	}
+	if *g.at != "" {
+		if opts.Rotate == "" {
+			return goofy.Options{}, fmt.Errorf("-at requires -rotate")
+		}
+		at, err := parseDate(*g.at)
+		if err != nil {
+			return goofy.Options{}, err
+		}
+		opts.At = at
+	}
+	if opts.MaxBytes == 0 {
+		opts.MaxBytes = goofy.NoTruncation
+	}
+	if *g.ranges != "" {
+		ranges, err := parseNamespaceRanges(*g.ranges)
+		if err != nil {
+			return goofy.Options{}, fmt.Errorf("-namespace-ranges: %v", err)
+		}
+		switch {
+		case opts.Namespace != "":
+			r, ok := ranges[opts.Namespace]
+			if !ok {
+				return goofy.Options{}, fmt.Errorf("namespace %q has no range in -namespace-ranges", opts.Namespace)
+			}
+			opts.Range = &r
+		case !g.requestNamespaces:
+			return goofy.Options{}, errors.New("-namespace-ranges requires -namespace")
+		}
+	}
+	key := *g.key
+	if key == "" {
+		key = *g.hmacKey
+	}
+	if key != "" {
+		opts.Key = []byte(key)
+		if !isSet(g.fs, "algo") {
+			opts.Algo = "hmac-sha256"
+		}
+		if size := goofy.KeySize(opts.Algo); len(key) == 2*size {
+			if b, err := hex.DecodeString(key); err == nil {
+				opts.Key = b
+			}
+		}
+	}
+	if err := opts.Validate(); err != nil {
+		return goofy.Options{}, err
+	}
+	return opts, nil
+}
+
+// parseNamespaceRanges parses a -namespace-ranges list of NAME=LO-HI
+// pairs. The ranges must not overlap, so that an ID's value tells which
+// namespace it belongs to.
+func parseNamespaceRanges(s string) (map[string]goofy.Range, error) {
+	ranges := make(map[string]goofy.Range)
+	for _, item := range splitList(s) {
+		name, span, ok := strings.Cut(item, "=")
+		lo, hi, ok2 := strings.Cut(span, "-")
+		if !ok || !ok2 || name == "" {
+			return nil, fmt.Errorf("%q is not NAME=LO-HI", item)
+		}
+		var r goofy.Range
+		var err error
+		if r.Lo, err = strconv.ParseUint(lo, 10, 64); err == nil {
+			r.Hi, err = strconv.ParseUint(hi, 10, 64)
+		}
+		if err != nil || r.Lo > r.Hi {
+			return nil, fmt.Errorf("%q is not NAME=LO-HI with LO <= HI", item)
+		}
+		if _, dup := ranges[name]; dup {
+			return nil, fmt.Errorf("namespace %q given twice", name)
+		}
+		for other, o := range ranges {
+			if r.Overlaps(o) {
+				return nil, fmt.Errorf("ranges of %q and %q overlap", name, other)
+			}
+		}
+		ranges[name] = r
+	}
+	return ranges, nil
+}
+
+// dayDuration is a flag.Value for durations that, beyond the units of
+// time.ParseDuration, may be given in whole days, e.g. "90d".
+type dayDuration time.Duration
+
+func (d *dayDuration) String() string {
+	if v := time.Duration(*d); v != 0 && v%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", v/(24*time.Hour))
+	}
+	return time.Duration(*d).String()
+}
+
+func (d *dayDuration) Set(s string) error {
+	if n, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(n)
+		if err != nil || days < 0 || days > math.MaxInt64/int(24*time.Hour) {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		*d = dayDuration(days) * dayDuration(24*time.Hour)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	if v < 0 {
+		return fmt.Errorf("negative duration %q", s)
+	}
+	*d = dayDuration(v)
+	return nil
+}
+
+// addTTLFlag registers the -ttl flag of the things fs's command creates,
+// such as "registry entries", which last forever by default unless value
+// is positive.
+func addTTLFlag(fs *flag.FlagSet, things string, value time.Duration) *dayDuration {
+	ttl := (*dayDuration)(&value)
+	usage := fmt.Sprintf("let %s expire after `DURATION`, e.g. 90d or 36h", things)
+	if value == 0 {
+		usage += " (default never)"
+	}
+	fs.Var(ttl, "ttl", usage)
+	return ttl
+}
+
+// parseDate parses a -at DATE, either a calendar day (taken as its UTC
+// midnight) or an RFC 3339 timestamp.
+func parseDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid -at date %q, want 2006-01-02 or RFC 3339", s)
+	}
+	return t, nil
+}
+
+// isSet reports whether the named flag was given on the command line, in
+// the environment or in the configuration file.
+func isSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// onCommandLine reports whether the named flag was given on the command
+// line. Checks of flags that cannot be combined use it, so that a value
+// from the environment or configuration file is not mistaken for one the
+// user just typed.
+func onCommandLine(fs *flag.FlagSet, name string) bool {
+	return isSet(fs, name) && !defaulted[fs.Lookup(name)]
+}
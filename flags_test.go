@@ -0,0 +1,99 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+func TestParseNamespaceRanges(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    map[string]goofy.Range
+		wantErr bool
+	}{
+		{in: "", want: map[string]goofy.Range{}},
+		{in: "a=0-99", want: map[string]goofy.Range{"a": {Lo: 0, Hi: 99}}},
+		{in: "a=0-99,b=100-199", want: map[string]goofy.Range{"a": {Lo: 0, Hi: 99}, "b": {Lo: 100, Hi: 199}}},
+		{in: "a=5-5", want: map[string]goofy.Range{"a": {Lo: 5, Hi: 5}}},
+		{in: "a=0-99,b=99-199", wantErr: true},  // overlap
+		{in: "a=0-99,a=100-199", wantErr: true}, // duplicate
+		{in: "a=9-0", wantErr: true},            // LO > HI
+		{in: "a=0", wantErr: true},
+		{in: "=0-9", wantErr: true},
+		{in: "a", wantErr: true},
+		{in: "a=x-9", wantErr: true},
+		{in: "a=0-y", wantErr: true},
+		{in: "a=-1-9", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseNamespaceRanges(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseNamespaceRanges(%q) = %v, want an error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseNamespaceRanges(%q) = %v, %v, want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDayDurationSet(t *testing.T) {
+	const day = 24 * time.Hour
+	tests := []struct {
+		in      string
+		want    time.Duration
+		str     string
+		wantErr bool
+	}{
+		{in: "90d", want: 90 * day, str: "90d"},
+		{in: "0d", want: 0, str: "0s"},
+		{in: "1d", want: day, str: "1d"},
+		{in: "36h", want: 36 * time.Hour, str: "36h0m0s"},
+		{in: "48h", want: 2 * day, str: "2d"},
+		{in: "15m", want: 15 * time.Minute, str: "15m0s"},
+		{in: "0", want: 0, str: "0s"},
+		{in: "-1d", wantErr: true},
+		{in: "-5m", wantErr: true},
+		{in: "1.5d", wantErr: true},
+		{in: "d", wantErr: true},
+		{in: "soon", wantErr: true},
+		{in: "106752d", wantErr: true}, // beyond time.Duration's 106751 days
+	}
+	for _, tt := range tests {
+		var d dayDuration
+		err := d.Set(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q) = %v, want an error", tt.in, time.Duration(d))
+			}
+			continue
+		}
+		if err != nil || time.Duration(d) != tt.want {
+			t.Errorf("Set(%q) = %v, %v, want %v", tt.in, time.Duration(d), err, tt.want)
+		}
+		if got := d.String(); got != tt.str {
+			t.Errorf("Set(%q).String() = %q, want %q", tt.in, got, tt.str)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// outputFlags are the flags selecting how generated records are checked
+// and written, shared by gen, batch, dir, watch and plain goofy.
+type outputFlags struct {
+	fs        *flag.FlagSet
+	autoPlain bool // default to -plain unless stdout is a terminal
+	plain     *bool
+	color     *string
+	echo      *bool
+	nul       *bool
+	output    *string
+	table     *string
+	check     *bool
+	format    *string
+	qr        *string
+	alts      *int
+	prefix    *string
+	detect    *bool
+	risk      *float64
+	strict    *bool
+	warn      *bool
+	url       *urlFlags
+	dedupe    *bool
+	dedupeBy  *string
+	sort      *string
+	errorsTo  *string
+	// load, if set by the command, replaces -url in mapping inputs to the
+	// data their IDs are derived from.
+	load func(string) (string, error)
+	// errsFile is the -errors-to file once opened by emitter.
+	errsFile *os.File
+}
+
+// addOutputFlags registers the output flags on fs. With autoPlain IDs
+// are spaced only when written to a terminal unless -plain is given.
+func addOutputFlags(fs *flag.FlagSet, autoPlain bool) *outputFlags {
+	plainUsage := "output as plain 6-digit string"
+	if autoPlain {
+		plainUsage += " (default when stdout is not a terminal; -plain=false to space IDs anyway)"
+	}
+	return &outputFlags{
+		fs:        fs,
+		autoPlain: autoPlain,
+		plain:     fs.Bool("plain", false, plainUsage),
+		color:     fs.String("color", "auto", "highlight IDs and dim echoed inputs in text output: `WHEN` is "+strings.Join(colorModes, ", ")),
+		echo:      fs.Bool("echo", false, "prefix each ID with its input, separated by a tab"),
+		nul:       fs.Bool("0", false, "read and write NUL-separated records instead of lines"),
+		output:    fs.String("output", "text", "output `FORMAT`: text, csv, json (with errors about single inputs as objects), sql (INSERT statements), hex (the value an ID encodes), raw64 (the full hash), crockford32 (the hash in -digits Crockford Base32 symbols), proquint (32 bits of it as pronounceable quintets), emoji (the hash in -digits emoji), bubblebabble (the hash as an SSH-style fingerprint) or detail (each ID with how it was derived, for debugging)"),
+		table:     fs.String("table", "codes", "insert into `TABLE` with -output sql"),
+		check:     fs.Bool("crockford-check", false, "append Crockford's check symbol to each code with -output crockford32"),
+		format:    fs.String("format", "", "render each record with Go `TEMPLATE`; fields: Input, ID, Formatted, Algo, Namespace, Counter, Digest, Truncated, Hashed"),
+		qr:        fs.String("qr", "", "render each ID as a QR code: a PNG image in `FILE`, or blocks on stdout with \"-\""),
+		alts:      fs.Int("alts", 0, "emit `K` alternative IDs per input, the first being the regular ID, so one that is free can be picked"),
+		prefix:    fs.String("require-prefix", "", "mix the smallest counter into each hash that gives an ID starting with `PREFIX`, and output it as the nonce to verify the ID with"),
+		detect:    fs.Bool("detect-collisions", false, "report distinct inputs sharing an ID on stderr and exit with status 3"),
+		risk:      fs.Float64("collision-warn", 0.5, "warn on stderr once the inputs collide with a probability above `P` (0 to disable)"),
+		strict:    fs.Bool("strict-truncation", false, "fail with status 6 if an input exceeds -max-bytes"),
+		warn:      fs.Bool("warn-truncation", false, "warn on stderr about inputs exceeding -max-bytes"),
+		url:       addURLFlags(fs),
+		dedupe:    fs.Bool("dedupe", false, "emit each distinct input only once and report how many duplicates were dropped"),
+		sort:      fs.String("sort", "", "buffer all records and write them sorted by `KEY`: input or id (default input order, also with -jobs)"),
+		dedupeBy:  fs.String("dedupe-by", "input", "with -dedupe, consider inputs the same by `KEY`: input (after -normalize, -fold, -trim and -squash-spaces) or id"),
+		errorsTo:  fs.String("errors-to", "", "with -output json, write the error objects to `FILE` instead of among the records"),
+	}
+}
+
+// emitter returns the emitter writing records generated with opts to
+// stdout as selected by the flags.
+func (o *outputFlags) emitter(opts goofy.Options) (*emitter, error) {
+	plain := *o.plain
+	if o.autoPlain && !isSet(o.fs, "plain") {
+		plain = !isTerminal(os.Stdout)
+	}
+	opts.Spaced = !plain
+	load := o.load
+	if load == nil {
+		load = o.url.fetcher()
+	}
+	if load != nil && !isSet(o.fs, "max-bytes") {
+		// Resources and files are hashed whole unless asked otherwise.
+		opts.MaxBytes = goofy.NoTruncation
+	}
+	color, err := useColor(*o.color, os.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	if *o.alts < 0 {
+		return nil, fmt.Errorf("-alts must not be negative, got %d", *o.alts)
+	}
+	if *o.prefix != "" {
+		if *o.alts > 0 {
+			return nil, errors.New("-require-prefix cannot be combined with -alts")
+		}
+		if _, err := goofy.PrefixCounter("", *o.prefix, opts); err != nil {
+			return nil, fmt.Errorf("-require-prefix: %v", err)
+		}
+	}
+	if *o.risk < 0 || *o.risk >= 1 {
+		return nil, fmt.Errorf("-collision-warn must be in [0, 1), got %g", *o.risk)
+	}
+	if *o.dedupeBy != "input" && *o.dedupeBy != "id" {
+		return nil, fmt.Errorf("unknown -dedupe-by %q, want input or id", *o.dedupeBy)
+	}
+	if *o.sort != "" && *o.sort != "input" && *o.sort != "id" {
+		return nil, fmt.Errorf("unknown -sort %q, want input or id", *o.sort)
+	}
+	jsonOut := *o.output == "json" && *o.format == "" && *o.qr == ""
+	if *o.errorsTo != "" && !jsonOut {
+		return nil, errors.New("-errors-to requires -output json")
+	}
+	if *o.check && *o.output != "crockford32" {
+		return nil, errors.New("-crockford-check requires -output crockford32")
+	}
+
+	var out recordWriter
+	switch {
+	case onCommandLine(o.fs, "format") && onCommandLine(o.fs, "qr"):
+		return nil, errors.New("-format cannot be combined with -qr")
+	case *o.qr != "":
+		if onCommandLine(o.fs, "output") || onCommandLine(o.fs, "0") {
+			return nil, errors.New("-qr cannot be combined with -output or -0")
+		}
+		out = newQRWriter(os.Stdout, *o.qr, opts, *o.echo)
+	case *o.format != "":
+		if onCommandLine(o.fs, "output") || onCommandLine(o.fs, "echo") {
+			return nil, errors.New("-format cannot be combined with -output or -echo")
+		}
+		out, err = newTemplateWriter(os.Stdout, *o.format, opts, *o.nul)
+	default:
+		out, err = newRecordWriter(*o.output, os.Stdout, outputOptions{
+			echo:      *o.echo,
+			nul:       *o.nul,
+			color:     color,
+			namespace: opts.Namespace != "",
+			digest:    hasDigest(opts),
+			counter:   *o.alts > 0,
+			nonce:     *o.prefix != "",
+			table:     *o.table,
+			check:     *o.check,
+			opts:      opts,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	var errs *jsonWriter
+	switch {
+	case *o.errorsTo != "":
+		if o.errsFile, err = os.Create(*o.errorsTo); err != nil {
+			return nil, err
+		}
+		bw := bufio.NewWriter(o.errsFile)
+		errs = &jsonWriter{w: bw, enc: json.NewEncoder(bw)}
+	case jsonOut:
+		// Errors go among the records, in input order.
+		errs = out.(*jsonWriter)
+	}
+	if *o.sort != "" {
+		out = &sortWriter{out: out, byID: *o.sort == "id"}
+	}
+
+	e := &emitter{
+		opts:   opts,
+		out:    out,
+		load:   load,
+		alts:   *o.alts,
+		prefix: *o.prefix,
+		strict: *o.strict,
+		warn:   *o.warn,
+		errs:   errs,
+	}
+	if *o.detect {
+		e.collisions = newCollisionDetector()
+	}
+	if *o.dedupe {
+		e.dedupe = &deduper{byID: *o.dedupeBy == "id", seen: make(map[string]struct{})}
+	}
+	if *o.risk > 0 {
+		e.risk = *o.risk
+		e.riskAt = goofy.SafeInputs(opts.Space(), e.risk) + 1
+	}
+	return e, nil
+}
+
+// generate writes the records of the inputs passed to emit by inputs,
+// generating them on jobs goroutines, and returns the process exit code.
+func (o *outputFlags) generate(opts goofy.Options, jobs int, inputs func(emit func(string) error) error) int {
+	e, code := o.start(opts)
+	if e == nil {
+		return code
+	}
+
+	emit := e.emit
+	var p *pool
+	if jobs > 1 {
+		p = newPool(e, jobs)
+		emit = p.add
+	}
+	err := inputs(emit)
+	if p != nil {
+		if perr := p.close(); err == nil {
+			err = perr
+		}
+	}
+	return o.finish(e, err)
+}
+
+// start returns the emitter writing records generated with opts, or nil
+// and the process exit code once it has reported why there is none.
+func (o *outputFlags) start(opts goofy.Options) (*emitter, int) {
+	e, err := o.emitter(opts)
+	if err != nil {
+		if code := exitCode(err); code != 1 {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return nil, code
+		}
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		o.fs.Usage()
+		return nil, 1
+	}
+	return e, 0
+}
+
+// finish flushes the output of e once its inputs are processed or err
+// stopped them, reports what went wrong with single inputs, and returns
+// the process exit code.
+func (o *outputFlags) finish(e *emitter, err error) int {
+	if err != nil && e.errs != nil {
+		// Best effort: the error may be that the stream is unwritable.
+		e.errs.WriteError(recordError{Error: "aborted", Message: err.Error()})
+	}
+	if ferr := e.out.Flush(); err == nil {
+		err = ferr
+	}
+	if o.errsFile != nil {
+		if ferr := e.errs.Flush(); err == nil {
+			err = ferr
+		}
+		if cerr := o.errsFile.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if e.dedupe != nil && e.dedupe.dropped > 0 {
+		slog.Info("dropped duplicate inputs", "count", e.dedupe.dropped)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+	if e.failed > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d input(s) failed\n", e.failed)
+		return exitCode(e.failure)
+	}
+	if e.collisions != nil && e.collisions.count > 0 {
+		fmt.Fprintf(os.Stderr, "Error: %d collision(s) detected\n", e.collisions.count)
+		return 3
+	}
+	return 0
+}
+
+// streamFlags are the flags tuning how record streams are read and
+// processed, shared by batch and plain goofy.
+type streamFlags struct {
+	maxRecord *int
+	jobs      *int
+	progress  *bool
+}
+
+// addStreamFlags registers the stream flags on fs.
+func addStreamFlags(fs *flag.FlagSet) *streamFlags {
+	return &streamFlags{
+		maxRecord: fs.Int("max-record", defaultMaxRecord, "fail cleanly on input records longer than `N` bytes"),
+		jobs:      fs.Int("jobs", 1, "generate IDs on `N` goroutines, preserving input order"),
+		progress:  fs.Bool("progress", false, "report records processed, throughput and, for files, the ETA on stderr"),
+	}
+}
+
+// validate checks the stream flags.
+func (s *streamFlags) validate() error {
+	if *s.jobs < 1 {
+		return fmt.Errorf("-jobs must be at least 1, got %d", *s.jobs)
+	}
+	return nil
+}
+
+// track returns inputs reporting its progress on stderr if -progress is
+// set. files are the inputs read, for estimating the time left; nil if
+// their size is unknown.
+func (s *streamFlags) track(files []string, inputs func(emit func(string) error) error) func(emit func(string) error) error {
+	if !*s.progress {
+		return inputs
+	}
+	return func(emit func(string) error) error {
+		p := startProgress(inputSize(files))
+		defer p.stop()
+		return inputs(p.wrap(emit))
+	}
+}
+
+// genCommand defines the flags of "goofy gen" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func genCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	out := addOutputFlags(fs, true)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s gen [options] <string>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Generate a 6-digit (or -digits N) hash ID from each string.\n")
+		fmt.Fprintf(os.Stderr, "Use \"%s batch\" to generate IDs for the records of files or stdin.\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s gen \"hello world!\"          # outputs: 25 91 44\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -plain \"hello world!\"   # outputs: 259144\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -digits 8 \"hello world\" # an 8-digit ID\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -echo a b c             # one \"input<TAB>ID\" line each\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -format '{{.ID}},{{.Algo}}' a b\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -namespace orders -output json 123\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -check-digit luhn \"hello world!\"  # outputs: 25 91 44 4\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s gen -qr label.png SKU-1234       # a QR code of the ID\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() < 1 {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument <string>\n\n")
+			fs.Usage()
+			return 1
+		}
+		if *out.qr != "" && *out.qr != "-" && fs.NArg() > 1 {
+			fmt.Fprintf(os.Stderr, "Error: -qr FILE holds a single ID; use -qr - for several <string> arguments\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		return out.generate(opts, 1, func(emit func(string) error) error {
+			return run(emit, inputOptions{}, "", fs.Args())
+		})
+	}
+}
+
+// batchCommand defines the flags of "goofy batch" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func batchCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	out := addOutputFlags(fs, true)
+	stream := addStreamFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s batch [options] [FILE...]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Generate an ID for every record of each FILE in turn, or of stdin if\n")
+		fmt.Fprintf(os.Stderr, "no FILE is given. A FILE of \"-\" denotes stdin. Records are lines, or\n")
+		fmt.Fprintf(os.Stderr, "NUL-separated with -0.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  cat names.txt | %s batch -plain  # one ID per line\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s batch -echo names.txt         # one \"input<TAB>ID\" line per line\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  find . -print0 | %s batch -0 | xargs -0 ...\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s batch -output csv -jobs 4 names.txt > ids.csv\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s batch -detect-collisions -digits 4 names.txt\n", os.Args[0])
+	}
+
+	return func() int {
+		opts, err := gen.options()
+		if err == nil {
+			err = stream.validate()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		files := fs.Args()
+		if len(files) == 0 {
+			files = []string{"-"}
+		}
+		in := inputOptions{nul: *out.nul, maxRecord: *stream.maxRecord}
+		return out.generate(opts, *stream.jobs, stream.track(files, func(emit func(string) error) error {
+			for _, name := range files {
+				if err := processFile(name, in, emit); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	}
+}
@@ -0,0 +1,195 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// gitHookSources are the inputs "goofy git-hook" can derive IDs from.
+var gitHookSources = []string{"message", "staged"}
+
+// gitHookScript is the commit-msg hook written by "goofy git-hook -install".
+const gitHookScript = `#!/bin/sh
+# Installed by "goofy git-hook -install": adds a %s trailer.
+exec %s git-hook %s"$1"
+`
+
+// gitHookCommand defines the flags of "goofy git-hook" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func gitHookCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	plain := fs.Bool("plain", false, "write the ID as plain 6-digit string")
+	from := fs.String("from", "message", "derive the ID from `SOURCE`: message (the commit message) or staged (the staged file paths)")
+	trailer := fs.String("trailer", "Change-ID", "add the ID as trailer `KEY`, unless the message has one already")
+	install := fs.Bool("install", false, "install goofy as the commit-msg hook of the current repository, passing it the other options given except -key")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s git-hook [options] MSGFILE\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s git-hook -install [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Run as a git commit-msg hook: generate an ID from the commit message in\n")
+		fmt.Fprintf(os.Stderr, "MSGFILE (without comments) or from the staged file paths, and add it to\n")
+		fmt.Fprintf(os.Stderr, "the message as a trailer such as \"Change-ID: 25 91 44\" using git\n")
+		fmt.Fprintf(os.Stderr, "interpret-trailers. Messages that have the trailer already, e.g. when\n")
+		fmt.Fprintf(os.Stderr, "amending, are left alone. Without MSGFILE the ID is printed instead.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s git-hook -install\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s git-hook -install -from staged -trailer Asset-Code\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s git-hook -from staged  # print the ID of the staged paths\n", os.Args[0])
+	}
+
+	return func() int {
+		opts, err := gen.options()
+		switch {
+		case err != nil:
+		case *from != "message" && *from != "staged":
+			err = fmt.Errorf("unknown -from %q, want one of %v", *from, gitHookSources)
+		case *install && fs.NArg() > 0:
+			err = fmt.Errorf("-install takes no MSGFILE")
+		case fs.NArg() > 1:
+			err = fmt.Errorf("unexpected argument %q", fs.Arg(1))
+		case fs.NArg() == 0 && *from == "message" && !*install:
+			err = fmt.Errorf("-from message requires MSGFILE")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		if *install {
+			err = installGitHook(fs)
+		} else {
+			opts.Spaced = !*plain
+			err = runGitHook(*from, *trailer, fs.Arg(0), opts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+}
+
+// runGitHook generates the ID of the source named from and adds it to
+// the commit message in msgFile as trailer key, or prints it if msgFile
+// is empty.
+func runGitHook(from, key, msgFile string, opts goofy.Options) error {
+	var input string
+	if from == "staged" {
+		out, err := git(nil, "diff", "--cached", "--name-only", "-z")
+		if err != nil {
+			return err
+		}
+		paths := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+		input = strings.Join(paths, "\n")
+	} else {
+		msg, err := os.ReadFile(msgFile)
+		if err != nil {
+			return err
+		}
+		if input, err = git(msg, "stripspace", "--strip-comments"); err != nil {
+			return err
+		}
+	}
+	if input == "" {
+		// Nothing to commit or an empty message, which git aborts on.
+		return nil
+	}
+	// Whole messages and path lists are hashed, not just their start.
+	opts.MaxBytes = goofy.NoTruncation
+	id, err := goofy.TryGenerate(input, opts)
+	if err != nil {
+		return err
+	}
+	if msgFile == "" {
+		fmt.Println(id)
+		return nil
+	}
+	_, err = git(nil, "interpret-trailers", "--in-place", "--if-exists", "doNothing", "--trailer", key+": "+id, msgFile)
+	return err
+}
+
+// installGitHook writes a commit-msg hook running goofy git-hook with the
+// flags set on fs to the current repository's hooks directory.
+func installGitHook(fs *flag.FlagSet) error {
+	dir, err := git(nil, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return err
+	}
+	dir = strings.TrimSpace(dir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, "commit-msg")
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s exists already; remove it or call goofy git-hook from it", path)
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var args strings.Builder
+	fs.Visit(func(f *flag.Flag) {
+		// Keys stay out of the script, which is readable to anyone with
+		// the repository; the hook picks them up from $GOOFY_KEY or the
+		// configuration file like any goofy run.
+		switch f.Name {
+		case "install", "key", "hmac-key":
+		default:
+			fmt.Fprintf(&args, "%s ", shellQuote("-"+f.Name+"="+f.Value.String()))
+		}
+	})
+	trailer := fs.Lookup("trailer").Value.String()
+	script := fmt.Sprintf(gitHookScript, trailer, shellQuote(self), args.String())
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "goofy: installed %s\n", path)
+	return nil
+}
+
+// git runs git with args, feeding it stdin, and returns its output.
+func git(stdin []byte, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
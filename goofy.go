@@ -19,103 +19,423 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"os"
-	"unicode/utf8"
-)
 
-const (
-	// MaxBytes is the maximum number of UTF-8 bytes processed
-	MaxBytes = 32
+	"github.com/al-maisan/goofy/pkg/goofy"
 )
 
-// sixDigitID generates a 6-digit ID from a string using FNV-1a hash.
-// It processes up to the first MaxBytes (32) bytes of UTF-8 encoding,
-// ensuring multibyte sequences are not split.
-//
-// Returns a 6-digit string (000000-999999).
-// Collisions are expected and acceptable.
-func sixDigitID(s string) string {
-	const (
-		offset64 = 1469598103934665603
-		prime64  = 1099511628211
-	)
+// command is a goofy subcommand.
+type command struct {
+	name    string
+	summary string
+	// setup defines the command's flags on fs and returns the function
+	// running it once they are parsed, which returns the exit code.
+	setup func(fs *flag.FlagSet) func() int
+}
 
-	// Truncate to MaxBytes without splitting UTF-8 sequences
-	truncated := truncateUTF8(s, MaxBytes)
+// commands lists the subcommands of goofy, in the order they are
+// documented. It is filled in by init because completion refers to it.
+var commands []command
 
-	var h uint64 = offset64
-	for i := 0; i < len(truncated); i++ {
-		h ^= uint64(truncated[i])
-		h *= prime64
+func init() {
+	commands = []command{
+		{"gen", "generate IDs for strings", genCommand},
+		{"batch", "generate IDs for the records of files or stdin", batchCommand},
+		{"csv", "add an ID column to a CSV file", csvCommand},
+		{"jsonl", "add an ID field to JSON lines", jsonlCommand},
+		{"kafka", "add IDs to the messages of a Kafka topic", kafkaCommand},
+		{"git-hook", "add ID trailers to commit messages as a git hook", gitHookCommand},
+		{"dir", "emit IDs for the files of a directory tree", dirCommand},
+		{"verify", "check that a string has a given ID", verifyCommand},
+		{"vanity", "search for a suffix giving a string a chosen ID", vanityCommand},
+		{"totp", "print or check a code that changes every time window", totpCommand},
+		{"token", "print self-contained expiring tokens for strings", tokenCommand},
+		{"verify-token", "check an expiring token offline", verifyTokenCommand},
+		{"serve", "serve IDs over HTTP and gRPC", serveCommand},
+		{"register", "generate IDs and record them in a registry", registerCommand},
+		{"lookup", "print the registered input(s) of an ID", lookupCommand},
+		{"registry", "export, import and maintain a registry", registryCommand},
+		{"crack", "search a wordlist for inputs having given IDs", crackCommand},
+		{"check", "validate the check digit of IDs", checkCommand},
+		{"analyze", "report collisions and ID spread for a corpus", analyzeCommand},
+		{"collide", "report the pairs of inputs sharing an ID", collideCommand},
+		{"diff", "compare the IDs of two versions of a dataset", diffCommand},
+		{"birthday", "compute the collision risk for a number of inputs", birthdayCommand},
+		{"bench", "measure the throughput of the hash algorithms", benchCommand},
+		{"selftest", "check known answers and hash avalanche behaviour", selftestCommand},
+		{"vectors", "export test vectors for reimplementations", vectorsCommand},
+		{"watch", "emit IDs for lines appended to a file", watchCommand},
+		{"repl", "print IDs interactively, changing options on the fly", replCommand},
+		{"completion", "print a shell completion script", completionCommand},
 	}
-	return fmt.Sprintf("%06d", h%1_000_000)
 }
 
-// truncateUTF8 truncates s to at most maxBytes bytes,
-// ensuring we don't split a multibyte UTF-8 sequence.
-func truncateUTF8(s string, maxBytes int) string {
-	if len(s) <= maxBytes {
-		return s
+// printCommands lists cmds with their summaries on stderr, the summaries
+// aligned after the longest name.
+func printCommands(cmds []command) {
+	width := 0
+	for _, c := range cmds {
+		width = max(width, len(c.name))
 	}
+	for _, c := range cmds {
+		fmt.Fprintf(os.Stderr, "  %-*s  %s\n", width, c.name, c.summary)
+	}
+}
 
-	// Find the last valid rune boundary at or before maxBytes
-	for i := maxBytes; i > 0; i-- {
-		if utf8.RuneStart(s[i]) {
-			return s[:i]
+func main() {
+	name, args, setup := "goofy", os.Args[1:], rootCommand
+	if len(args) > 0 {
+		for _, c := range commands {
+			if args[0] == c.name {
+				name, args, setup = c.name, args[1:], c.setup
+				break
+			}
 		}
 	}
-
-	// If we can't find a valid boundary, return empty
-	return ""
+	code := runCommand(name, setup, args)
+	// Let -algo exec: helpers exit before goofy does.
+	if err := goofy.CloseHelpers(); err != nil {
+		slog.Warn("hash helper failed", "err", err)
+	}
+	os.Exit(code)
 }
 
-// formatSpaced formats a 6-digit ID as "XX XX XX"
-func formatSpaced(id string) string {
-	if len(id) != 6 {
-		return id
+// runCommand sets up the named command, parses args into its flags,
+// fills in the flags not given from the environment and configuration
+// file, installs the logger and runs it, returning the process exit code.
+// A failing -algo exec: helper the command does not handle itself fails
+// it with exit code 5.
+func runCommand(name string, setup func(fs *flag.FlagSet) func() int, args []string) (code int) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	logs := addLogFlags(fs)
+	run := setup(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 1
+	}
+	if err := applyDefaults(fs, name); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := logs.install(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+		fs.Usage()
+		return 1
 	}
-	return fmt.Sprintf("%s %s %s", id[0:2], id[2:4], id[4:6])
+	if err := goofy.Catch(func() { code = run() }); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitCode(err)
+	}
+	return code
 }
 
-func main() {
-	plain := flag.Bool("plain", false, "output as plain 6-digit string")
-	help := flag.Bool("h", false, "show help")
+// rootCommand defines the flags of plain "goofy" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code. It predates the subcommands and combines gen and batch for
+// compatibility: IDs are generated for the <string> arguments, or for the
+// records of -f FILE or stdin.
+func rootCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	out := addOutputFlags(fs, false)
+	stream := addStreamFlags(fs)
+	verify := fs.String("verify", "", "check that <string> has the given `ID` instead of printing it")
+	file := fs.String("f", "", "read newline-separated inputs from `FILE` (\"-\" for stdin)")
+	help := fs.Bool("h", false, "show help")
 
-	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [options] <string>\n\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "Generate a 6-digit hash ID from a string.\n\n")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s <command> [options] [arguments]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [options] <string>...  (same as gen)\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [options] -f FILE      (same as batch FILE)\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Generate 6-digit (or -digits N) hash IDs from strings.\n")
+		fmt.Fprintf(os.Stderr, "Without a command, if no <string> is given and stdin is not a\n")
+		fmt.Fprintf(os.Stderr, "terminal, one ID is generated per line read from stdin.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		printCommands(commands)
+		fmt.Fprintf(os.Stderr, "\nRun \"%s <command> -h\" for the options of a command.\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Options:\n")
-		flag.PrintDefaults()
+		fs.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nExamples:\n")
-		fmt.Fprintf(os.Stderr, "  %s \"hello world\"        # outputs: 25 91 44\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s -plain \"hello world\" # outputs: 259144\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s \"hello world!\"          # outputs: 25 91 44\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -plain \"hello world!\"   # outputs: 259144\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  cat names.txt | %s -plain  # one ID per line\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s batch -echo names.txt   # one \"input<TAB>ID\" line per line\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  GOOFY_KEY=secret %s verify \"hello world\" 123456\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -url https://example.com/doc.pdf  # an ID of the document\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
 		fmt.Fprintf(os.Stderr, "  0 - success\n")
-		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed input\n")
+		fmt.Fprintf(os.Stderr, "  2 - ID does not match (verify, -verify, check)\n")
+		fmt.Fprintf(os.Stderr, "  3 - collision detected (-detect-collisions)\n")
+		fmt.Fprintf(os.Stderr, "  4 - ID already registered to another input (register)\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O error: unreadable input, unwritable output, unreachable\n")
+		fmt.Fprintf(os.Stderr, "      registry, server or broker\n")
+		fmt.Fprintf(os.Stderr, "  6 - input exceeds -max-bytes (-strict-truncation)\n")
+	}
+
+	return func() int {
+		if *help {
+			fs.Usage()
+			return 0
+		}
+
+		opts, err := gen.options()
+		if err == nil {
+			err = stream.validate()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		if *verify != "" {
+			if *file != "" || fs.NArg() != 1 {
+				fmt.Fprintf(os.Stderr, "Error: -verify requires exactly one <string> argument\n\n")
+				fs.Usage()
+				return 1
+			}
+			s := fs.Arg(0)
+			if fetch := out.url.fetcher(); fetch != nil {
+				if !isSet(fs, "max-bytes") {
+					opts.MaxBytes = goofy.NoTruncation
+				}
+				if s, err = fetch(s); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					return exitCode(err)
+				}
+			}
+			ok, err := goofy.TryVerify(s, *verify, opts)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+			if !ok {
+				fmt.Fprintf(os.Stderr, "Error: ID %s does not match\n", *verify)
+				return 2
+			}
+			return 0
+		}
+
+		if *file != "" && fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: -f cannot be combined with <string> arguments\n\n")
+			fs.Usage()
+			return 1
+		}
+		if *file == "" && fs.NArg() < 1 && isTerminal(os.Stdin) {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument <string>\n\n")
+			fs.Usage()
+			return 1
+		}
+
+		var files []string
+		switch {
+		case *file != "":
+			files = []string{*file}
+		case fs.NArg() == 0:
+			files = []string{"-"}
+		}
+		in := inputOptions{nul: *out.nul, maxRecord: *stream.maxRecord}
+		return out.generate(opts, *stream.jobs, stream.track(files, func(emit func(string) error) error {
+			return run(emit, in, *file, fs.Args())
+		}))
+	}
+}
+
+// run calls emit for the inputs named on the command line: the records
+// of file if set, the positional args if any, and the records of stdin
+// otherwise.
+func run(emit func(string) error, in inputOptions, file string, args []string) error {
+	switch {
+	case file != "":
+		return processFile(file, in, emit)
+	case len(args) > 0:
+		for _, word := range args {
+			if err := emit(word); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return processFile("-", in, emit)
 	}
+}
+
+// errTruncated is returned by emit for inputs exceeding -max-bytes under
+// -strict-truncation.
+var errTruncated = errors.New("input exceeds -max-bytes")
 
-	flag.Parse()
+// ioError marks an error as an I/O error for exitCode where its type does
+// not tell, e.g. an HTTP error response.
+type ioError struct{ error }
 
-	if *help {
-		flag.Usage()
-		os.Exit(0)
+func (e ioError) Unwrap() error { return e.error }
+
+// exitCode returns the exit code for err, the error a command failed with
+// after its arguments were checked: 6 for inputs exceeding -max-bytes
+// under -strict-truncation, 5 for failures reading or writing files,
+// streams or the network, and 1 for anything else, e.g. malformed input.
+func exitCode(err error) int {
+	var (
+		pathErr *os.PathError
+		linkErr *os.LinkError
+		sysErr  *os.SyscallError
+		netErr  net.Error
+		ioErr   ioError
+		execErr *goofy.ExecError
+	)
+	switch {
+	case errors.Is(err, errTruncated):
+		return 6
+	case errors.As(err, &pathErr), errors.As(err, &linkErr), errors.As(err, &sysErr),
+		errors.As(err, &netErr), errors.As(err, &ioErr), errors.As(err, &execErr),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return 5
 	}
+	return 1
+}
+
+// emitter generates IDs and hands them to a recordWriter.
+type emitter struct {
+	opts       goofy.Options
+	out        recordWriter
+	alts       int                // candidates per input, 0 for just the ID
+	prefix     string             // -require-prefix, "" for none
+	strict     bool               // fail on truncated inputs
+	warn       bool               // warn about truncated inputs
+	collisions *collisionDetector // nil unless -detect-collisions
+	dedupe     *deduper           // nil unless -dedupe
+	risk       float64            // -collision-warn probability
+	riskAt     uint64             // inputs beyond which to warn, 0 for never
+	inputs     uint64             // inputs written so far
+	// load, if set, returns the data the IDs of an input are derived
+	// from: the resource at a URL with -url, a file's content with dir
+	// -content.
+	load func(string) (string, error)
+	// errs, if set, receives the problems with single inputs as JSON
+	// objects, and processing continues with the next input.
+	errs    *jsonWriter
+	failed  int   // inputs reported to errs
+	failure error // the first of them, for the exit code
+}
 
-	if flag.NArg() < 1 {
-		fmt.Fprintf(os.Stderr, "Error: missing required argument <string>\n\n")
-		flag.Usage()
-		os.Exit(1)
+// fail reports the problem re with a single input caused by err: to
+// e.errs if set, so that processing continues, and as err otherwise.
+func (e *emitter) fail(re recordError, err error) error {
+	if e.errs == nil {
+		return err
+	}
+	if e.failure == nil {
+		e.failure = err
 	}
+	e.failed++
+	return e.errs.WriteError(re)
+}
 
-	word := flag.Arg(0)
-	id := sixDigitID(word)
+// emit generates the ID(s) for input and writes them out.
+func (e *emitter) emit(input string) error {
+	recs, err := e.records(input)
+	if err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		if err := e.write(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// -plain overrides -spaced
-	if *plain {
-		fmt.Println(id)
-	} else {
-		fmt.Println(formatSpaced(id))
+// records generates the record for input or, with -alts, one record per
+// alternative counter. With -require-prefix the record's ID is that of the
+// first counter giving the prefix. With e.load the IDs are derived from
+// the data it loads for input. It is safe for concurrent use.
+func (e *emitter) records(input string) (_ []record, err error) {
+	data := input
+	if e.load != nil {
+		if data, err = e.load(input); err != nil {
+			if e.errs == nil {
+				return nil, err
+			}
+			return []record{{Input: input, err: err}}, nil
+		}
+	}
+	if e.prefix != "" {
+		opts := e.opts
+		var prefixErr error
+		if err := goofy.Catch(func() {
+			opts.Counter, prefixErr = goofy.PrefixCounter(data, e.prefix, opts)
+		}); err != nil {
+			return nil, ioError{err}
+		}
+		if prefixErr != nil {
+			return nil, prefixErr
+		}
+		rec, err := newRecord(data, opts)
+		if err != nil {
+			return nil, ioError{err}
+		}
+		rec.Input = input
+		rec.Nonce = &opts.Counter
+		return []record{rec}, nil
+	}
+	recs := make([]record, max(e.alts, 1))
+	for n := range recs {
+		opts := e.opts
+		opts.Counter = n
+		if recs[n], err = newRecord(data, opts); err != nil {
+			return nil, ioError{err}
+		}
+		recs[n].Input = input
+		if e.alts > 0 {
+			recs[n].Counter = &n
+		}
+	}
+	return recs, nil
+}
+
+// write checks rec for truncation and collisions and writes it out,
+// unless it is a duplicate dropped by -dedupe.
+func (e *emitter) write(rec record) error {
+	input := rec.Input
+	if rec.err != nil {
+		return e.fail(recordError{Error: "load", Input: input, Message: rec.err.Error()}, rec.err)
+	}
+	if e.dedupe != nil && e.dedupe.drop(rec, e.opts) {
+		return nil
+	}
+	if rec.Truncated {
+		switch {
+		case e.strict:
+			return e.fail(recordError{Error: "truncated", Input: input}, fmt.Errorf("%q: %w", input, errTruncated))
+		case e.warn:
+			slog.Warn("input exceeds -max-bytes and was truncated", "input", input)
+		}
+	}
+	if e.collisions != nil {
+		if first, ok := e.collisions.check(input, rec.ID); ok {
+			if e.errs != nil {
+				if err := e.errs.WriteError(recordError{Error: "collision", Input: input, ID: rec.ID, First: first}); err != nil {
+					return err
+				}
+			} else {
+				slog.Warn("collision", "id", rec.ID, "first", first, "input", input)
+			}
+		}
+	}
+	if rec.Counter == nil || *rec.Counter == 0 {
+		e.inputs++
+		if e.inputs == e.riskAt {
+			slog.Warn("inputs likely to collide; consider a larger -digits (see goofy birthday)",
+				"inputs", e.riskAt-1, "probability", e.risk, "ids", e.opts.Space())
+		}
 	}
+	return e.out.Write(rec)
 }
@@ -0,0 +1,187 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+//go:generate buf generate
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	goofyv1 "github.com/al-maisan/goofy/api/goofy/v1"
+	"github.com/al-maisan/goofy/internal/registry"
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// idService implements the goofy.v1.IDService gRPC service.
+type idService struct {
+	goofyv1.UnimplementedIDServiceServer
+	srv *server
+}
+
+// newGRPCServer returns a gRPC server exposing s as goofy.v1.IDService,
+// over TLS if s.tls is set.
+func newGRPCServer(s *server) *grpc.Server {
+	var opts []grpc.ServerOption
+	if s.accessLog {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.logUnary), grpc.ChainStreamInterceptor(s.logStream))
+	}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.metrics.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.metrics.streamInterceptor),
+	)
+	if s.keys != nil {
+		opts = append(opts, grpc.ChainUnaryInterceptor(s.keyUnary), grpc.ChainStreamInterceptor(s.keyStream))
+	}
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(s.limiter.unaryInterceptor),
+		grpc.ChainStreamInterceptor(s.limiter.streamInterceptor),
+	)
+	if s.tls != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(s.tls)))
+	}
+	gs := grpc.NewServer(opts...)
+	goofyv1.RegisterIDServiceServer(gs, &idService{srv: s})
+	return gs
+}
+
+// Generate returns the ID of a single input.
+func (s *idService) Generate(ctx context.Context, req *goofyv1.GenerateRequest) (*goofyv1.GenerateResponse, error) {
+	opts, err := s.options(req.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.srv.record(ctx, req.GetInput(), opts)
+	if err != nil {
+		return nil, recordStatusError(err)
+	}
+	return toProto(rec), nil
+}
+
+// GenerateStream streams the IDs of req's inputs in order, stopping early
+// if the client goes away or its deadline expires.
+func (s *idService) GenerateStream(req *goofyv1.GenerateStreamRequest, stream grpc.ServerStreamingServer[goofyv1.GenerateResponse]) error {
+	ctx := stream.Context()
+	opts, err := s.options(req.GetNamespace())
+	if err != nil {
+		return err
+	}
+	s.srv.metrics.batchSize.WithLabelValues(goofyv1.IDService_GenerateStream_FullMethodName).Observe(float64(len(req.GetInputs())))
+	for _, input := range req.GetInputs() {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		rec, err := s.srv.record(ctx, input, opts)
+		if err != nil {
+			return recordStatusError(err)
+		}
+		if err := stream.Send(toProto(rec)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BulkGenerate answers every request streamed by the client with the ID
+// of its input, in order, until the client closes its side. An input whose
+// ID is registered to another input is answered with the conflict in the
+// response's error and the stream goes on; any other error ends the call.
+func (s *idService) BulkGenerate(stream grpc.BidiStreamingServer[goofyv1.GenerateRequest, goofyv1.GenerateResponse]) error {
+	n := 0
+	defer func() {
+		s.srv.metrics.batchSize.WithLabelValues(goofyv1.IDService_BulkGenerate_FullMethodName).Observe(float64(n))
+	}()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		opts, err := s.options(req.GetNamespace())
+		if err != nil {
+			return err
+		}
+		// Send blocks while the client's flow control window is full,
+		// so nothing more is read until the client catches up.
+		resp, err := s.bulkResponse(stream.Context(), req.GetInput(), opts)
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		n++
+	}
+}
+
+// bulkResponse returns the BulkGenerate response for input: its ID, or a
+// registry conflict in the error field. Other errors of server.record are
+// returned as a gRPC status.
+func (s *idService) bulkResponse(ctx context.Context, input string, opts goofy.Options) (*goofyv1.GenerateResponse, error) {
+	rec, err := s.srv.record(ctx, input, opts)
+	var conflict *registry.ConflictError
+	switch {
+	case errors.As(err, &conflict):
+		return &goofyv1.GenerateResponse{
+			Input:     input,
+			Namespace: opts.Namespace,
+			Error:     err.Error(),
+		}, nil
+	case err != nil:
+		return nil, recordStatusError(err)
+	}
+	return toProto(rec), nil
+}
+
+// options returns the generator options of requests for namespace, or an
+// InvalidArgument status if there are none.
+func (s *idService) options(namespace string) (goofy.Options, error) {
+	opts, err := s.srv.options(namespace)
+	if err != nil {
+		return goofy.Options{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return opts, nil
+}
+
+// recordStatusError converts an error of server.record to a gRPC status:
+// AlreadyExists for a registry conflict, Unavailable otherwise.
+func recordStatusError(err error) error {
+	var conflict *registry.ConflictError
+	if errors.As(err, &conflict) {
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// toProto converts rec to its wire representation.
+func toProto(rec record) *goofyv1.GenerateResponse {
+	return &goofyv1.GenerateResponse{
+		Input:     rec.Input,
+		Id:        rec.ID,
+		Namespace: rec.Namespace,
+	}
+}
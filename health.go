@@ -0,0 +1,69 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// readyTimeout bounds the registry check of GET /readyz.
+const readyTimeout = 2 * time.Second
+
+// healthResponse is the body of a successful GET /healthz or /readyz.
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// handleHealthz serves GET /healthz: the process is up and answering. It
+// deliberately checks nothing else, so that a registry outage makes the
+// instance unready rather than getting it restarted.
+func (s *server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, healthResponse{"ok"})
+}
+
+// handleReadyz serves GET /readyz: the instance can answer ID requests,
+// which with -registry takes a reachable registry, and is not shutting
+// down.
+func (s *server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if s.draining.Load() {
+		writeError(w, http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+	if s.reg != nil {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		if err := s.reg.Ping(ctx); err != nil {
+			writeError(w, http.StatusServiceUnavailable, "registry unreachable: "+err.Error())
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, healthResponse{"ok"})
+}
@@ -0,0 +1,114 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// isTerminal reports whether f is attached to a terminal (as opposed to
+// a pipe or a redirected file).
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// defaultMaxRecord is the default limit on the length of a single input
+// record read from a file or stdin.
+const defaultMaxRecord = 1 << 20
+
+// inputOptions tunes the input readers.
+type inputOptions struct {
+	nul       bool // read NUL-separated records instead of lines
+	maxRecord int  // longest accepted record in bytes; 0 means defaultMaxRecord
+}
+
+// processLines calls fn for every line (or NUL-separated record if
+// in.nul is set) read from r, stopping at the first error. Records are
+// streamed, so memory use is bounded by in.maxRecord rather than by the
+// size of the input; longer records are reported as an error.
+func processLines(r io.Reader, in inputOptions, fn func(string) error) error {
+	max := in.maxRecord
+	if max <= 0 {
+		max = defaultMaxRecord
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(max, bufio.MaxScanTokenSize)), max)
+	if in.nul {
+		scanner.Split(scanNUL)
+	}
+	n := 0
+	for scanner.Scan() {
+		n++
+		if err := fn(scanner.Text()); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("record %d exceeds the maximum record length of %d bytes", n+1, max)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// scanNUL is a bufio.SplitFunc that returns NUL-terminated records.
+// The final record need not be terminated.
+func scanNUL(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+// processFile calls fn for every record of the named file like
+// processLines; "-" denotes stdin.
+func processFile(name string, in inputOptions, fn func(string) error) error {
+	if name == "-" {
+		if err := processLines(os.Stdin, in, fn); err != nil {
+			return fmt.Errorf("reading stdin: %w", err)
+		}
+		return nil
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := processLines(f, in, fn); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+	berrors "go.etcd.io/bbolt/errors"
+)
+
+// boltOpenTimeout bounds the wait for another process to release the
+// database file, which bbolt locks while it is open.
+const boltOpenTimeout = 5 * time.Second
+
+// boltBucket returns the name of the bucket holding the IDs of namespace;
+// the default namespace is "ids", any other "ids/NAMESPACE", so that each
+// namespace's IDs live apart.
+func boltBucket(namespace string) []byte {
+	if namespace == "" {
+		return []byte("ids")
+	}
+	return []byte("ids/" + namespace)
+}
+
+// boltNamespace is the inverse of boltBucket: it returns the namespace
+// whose IDs the bucket name holds, and whether it holds IDs at all.
+func boltNamespace(name []byte) (string, bool) {
+	if bytes.Equal(name, []byte("ids")) {
+		return "", true
+	}
+	namespace, ok := bytes.CutPrefix(name, []byte("ids/"))
+	return string(namespace), ok
+}
+
+// forEachNamespace calls fn with the bucket of every namespace, in
+// ascending order of namespace; bucket names sort bytewise, and "ids"
+// before any "ids/NAMESPACE".
+func forEachNamespace(tx *bolt.Tx, fn func(namespace string, b *bolt.Bucket) error) error {
+	return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		namespace, ok := boltNamespace(name)
+		if !ok {
+			return nil
+		}
+		return fn(namespace, b)
+	})
+}
+
+// boltRegistry is a Registry backed by a bbolt database file: pure Go,
+// without cgo, with every registration a crash-safe transaction. IDs are
+// keys of a bucket per namespace whose values are JSON storedEntry
+// objects.
+type boltRegistry struct {
+	db *bolt.DB
+}
+
+func openBolt(path string) (*boltRegistry, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: boltOpenTimeout})
+	if errors.Is(err, berrors.ErrTimeout) {
+		return nil, fmt.Errorf("opening %s: still in use by another process after %v", path, boltOpenTimeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", path, err)
+	}
+	return &boltRegistry{db: db}, nil
+}
+
+func (r *boltRegistry) Register(ctx context.Context, namespace, id, input string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := r.Import(ctx, Entry{Namespace: namespace, ID: id, Input: input, CreatedAt: now, ExpiresAt: expiry(now, ttl)})
+	return err
+}
+
+func (r *boltRegistry) Import(ctx context.Context, e Entry) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	added := false
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(boltBucket(e.Namespace))
+		if err != nil {
+			return err
+		}
+		if value := b.Get([]byte(e.ID)); value != nil {
+			existing, err := decodeEntry(e.Namespace, e.ID, value)
+			if err != nil {
+				return err
+			}
+			if !existing.Expired(time.Now()) {
+				if existing.Input != e.Input {
+					return &ConflictError{Namespace: e.Namespace, ID: e.ID, Input: e.Input, Existing: existing.Input}
+				}
+				return nil
+			}
+		}
+		value, err := encodeEntry(e)
+		if err != nil {
+			return err
+		}
+		added = true
+		return b.Put([]byte(e.ID), value)
+	})
+	return added, err
+}
+
+func (r *boltRegistry) Lookup(ctx context.Context, namespace, id string) ([]Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	err := r.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(boltBucket(namespace))
+		if b == nil {
+			return nil
+		}
+		value := b.Get([]byte(id))
+		if value == nil {
+			return nil
+		}
+		e, err := decodeEntry(namespace, id, value)
+		if err != nil || e.Expired(time.Now()) {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+func (r *boltRegistry) Walk(ctx context.Context, fn func(Entry) error) error {
+	now := time.Now()
+	return r.db.View(func(tx *bolt.Tx) error {
+		return forEachNamespace(tx, func(namespace string, b *bolt.Bucket) error {
+			// Keys are sorted bytewise, which for IDs is ascending order.
+			return b.ForEach(func(k, v []byte) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				e, err := decodeEntry(namespace, string(k), v)
+				if err != nil || e.Expired(now) {
+					return err
+				}
+				return fn(e)
+			})
+		})
+	})
+}
+
+func (r *boltRegistry) Purge(ctx context.Context, now time.Time) (int, error) {
+	n := 0
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		return forEachNamespace(tx, func(namespace string, b *bolt.Bucket) error {
+			// Deleting while iterating skips keys, so collect them first.
+			var expired [][]byte
+			err := b.ForEach(func(k, v []byte) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				e, err := decodeEntry(namespace, string(k), v)
+				if err == nil && e.Expired(now) {
+					expired = append(expired, k)
+				}
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			for _, k := range expired {
+				if err := b.Delete(k); err != nil {
+					return err
+				}
+			}
+			n += len(expired)
+			return nil
+		})
+	})
+	return n, err
+}
+
+func (r *boltRegistry) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// View fails once the database has been closed.
+	return r.db.View(func(*bolt.Tx) error { return nil })
+}
+
+func (r *boltRegistry) Close() error {
+	return r.db.Close()
+}
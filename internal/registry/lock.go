@@ -0,0 +1,79 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package registry
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// lockPollInterval is how often a contended file lock is retried.
+const lockPollInterval = 10 * time.Millisecond
+
+// fileLock is an advisory lock on a file (flock on Unix, LockFileEx on
+// Windows) that serializes a critical section across processes, and with
+// a mutex across the goroutines of this one.
+type fileLock struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+// openFileLock opens the lock file at path, creating it if necessary.
+func openFileLock(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLock{f: f}, nil
+}
+
+// lock acquires the lock exclusively, waiting for other processes to
+// release it until ctx is done.
+func (l *fileLock) lock(ctx context.Context) error {
+	l.mu.Lock()
+	for {
+		ok, err := tryLockFile(l.f)
+		if err != nil {
+			l.mu.Unlock()
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			l.mu.Unlock()
+			return ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// unlock releases the lock.
+func (l *fileLock) unlock() error {
+	defer l.mu.Unlock()
+	return unlockFile(l.f)
+}
+
+// close releases the lock file, and with it any lock held.
+func (l *fileLock) close() error {
+	return l.f.Close()
+}
@@ -0,0 +1,35 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+//go:build !unix && !windows
+
+package registry
+
+import "os"
+
+// tryLockFile succeeds at once: there are no advisory file locks on this
+// platform, so registries are only safe to use from one process at a
+// time.
+func tryLockFile(f *os.File) (bool, error) {
+	return true, nil
+}
+
+// unlockFile does nothing, see tryLockFile.
+func unlockFile(f *os.File) error {
+	return nil
+}
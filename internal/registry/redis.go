@@ -0,0 +1,192 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package registry
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix prefixes the Redis keys IDs of the default namespace are
+// registered under; redisNamespacePrefix those of other namespaces.
+const (
+	redisKeyPrefix       = "goofy:id:"
+	redisNamespacePrefix = "goofy:ns:"
+)
+
+// redisKey returns the key id is registered under in namespace:
+// goofy:id:ID in the default namespace, else goofy:ns:NAMESPACE:id:ID
+// with the namespace query-escaped, so that it holds no colon.
+func redisKey(namespace, id string) string {
+	if namespace == "" {
+		return redisKeyPrefix + id
+	}
+	return redisNamespacePrefix + url.QueryEscape(namespace) + ":id:" + id
+}
+
+// parseRedisKey is the inverse of redisKey.
+func parseRedisKey(key string) (namespace, id string, ok bool) {
+	if id, ok := strings.CutPrefix(key, redisKeyPrefix); ok {
+		return "", id, true
+	}
+	rest, ok := strings.CutPrefix(key, redisNamespacePrefix)
+	if !ok {
+		return "", "", false
+	}
+	escaped, id, ok := strings.Cut(rest, ":id:")
+	if !ok {
+		return "", "", false
+	}
+	namespace, err := url.QueryUnescape(escaped)
+	return namespace, id, err == nil && namespace != ""
+}
+
+// redisRegistry is a Registry backed by a Redis database, which several
+// goofy instances can share. IDs are claimed with SET NX, so concurrent
+// registrations of one ID cannot both succeed.
+type redisRegistry struct {
+	client *redis.Client
+}
+
+// quietLogger drops the Redis client's internal log messages; failures
+// reach callers as errors anyway.
+type quietLogger struct{}
+
+func (quietLogger) Printf(context.Context, string, ...any) {}
+
+func openRedis(url string) (*redisRegistry, error) {
+	redis.SetLogger(quietLogger{})
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("connecting to %s: %w", opts.Addr, err)
+	}
+	return &redisRegistry{client: client}, nil
+}
+
+func (r *redisRegistry) Register(ctx context.Context, namespace, id, input string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := r.Import(ctx, Entry{Namespace: namespace, ID: id, Input: input, CreatedAt: now, ExpiresAt: expiry(now, ttl)})
+	return err
+}
+
+// Import stores entries that expire with a Redis TTL, so that Redis
+// removes them itself.
+func (r *redisRegistry) Import(ctx context.Context, e Entry) (bool, error) {
+	var ttl time.Duration
+	if !e.ExpiresAt.IsZero() {
+		if ttl = time.Until(e.ExpiresAt); ttl <= 0 {
+			return false, nil // lapsed already, nothing to keep
+		}
+	}
+	value, err := encodeEntry(e)
+	if err != nil {
+		return false, err
+	}
+	ok, err := r.client.SetNX(ctx, redisKey(e.Namespace, e.ID), value, ttl).Result()
+	if err != nil || ok {
+		return ok, err
+	}
+	entries, err := r.Lookup(ctx, e.Namespace, e.ID)
+	if err != nil {
+		return false, err
+	}
+	if len(entries) == 0 {
+		return false, fmt.Errorf("ID %s vanished while registering %q", e.ID, e.Input)
+	}
+	if existing := entries[0].Input; existing != e.Input {
+		return false, &ConflictError{Namespace: e.Namespace, ID: e.ID, Input: e.Input, Existing: existing}
+	}
+	return false, nil
+}
+
+func (r *redisRegistry) Lookup(ctx context.Context, namespace, id string) ([]Entry, error) {
+	value, err := r.client.Get(ctx, redisKey(namespace, id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	e, err := decodeEntry(namespace, id, value)
+	if err != nil {
+		return nil, err
+	}
+	return []Entry{e}, nil
+}
+
+// Walk scans the registry's keys, then fetches their entries in order of
+// namespace and ID; entries registered meanwhile may be missed.
+func (r *redisRegistry) Walk(ctx context.Context, fn func(Entry) error) error {
+	type key struct{ namespace, id string }
+	var keys []key
+	for _, pattern := range []string{redisKeyPrefix + "*", redisNamespacePrefix + "*"} {
+		iter := r.client.Scan(ctx, 0, pattern, 1000).Iterator()
+		for iter.Next(ctx) {
+			if namespace, id, ok := parseRedisKey(iter.Val()); ok {
+				keys = append(keys, key{namespace, id})
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return err
+		}
+	}
+	slices.SortFunc(keys, func(a, b key) int {
+		return cmp.Or(strings.Compare(a.namespace, b.namespace), strings.Compare(a.id, b.id))
+	})
+	for _, k := range keys {
+		entries, err := r.Lookup(ctx, k.namespace, k.id)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Purge has nothing to do: Redis deletes expired entries itself.
+func (r *redisRegistry) Purge(ctx context.Context, now time.Time) (int, error) {
+	return 0, nil
+}
+
+func (r *redisRegistry) Ping(ctx context.Context) error {
+	return r.client.Ping(ctx).Err()
+}
+
+func (r *redisRegistry) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,154 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package registry persists input-to-ID assignments so that an ID, once
+// handed out, is never given to a different input. Every namespace has
+// an ID space of its own: the same ID may belong to different inputs in
+// different namespaces.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Entry is one registered input-to-ID assignment.
+type Entry struct {
+	Namespace string // "" for the default namespace
+	ID        string
+	Input     string
+	CreatedAt time.Time
+	// ExpiresAt, if not zero, is when the assignment lapses and the ID
+	// becomes free again.
+	ExpiresAt time.Time
+}
+
+// Expired reports whether e has lapsed at now.
+func (e Entry) Expired(now time.Time) bool {
+	return !e.ExpiresAt.IsZero() && !now.Before(e.ExpiresAt)
+}
+
+// expiry returns the time an entry registered now with ttl expires, or
+// the zero time if ttl is not positive.
+func expiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
+
+// ConflictError is returned by Register when the ID is already assigned
+// to a different input.
+type ConflictError struct {
+	Namespace string
+	ID        string
+	Input     string // input that was being registered
+	Existing  string // input the ID already belongs to
+}
+
+func (e *ConflictError) Error() string {
+	id := e.ID
+	if e.Namespace != "" {
+		id += fmt.Sprintf(" in namespace %q", e.Namespace)
+	}
+	return fmt.Sprintf("ID %s is already registered to %q, refusing to register %q", id, e.Existing, e.Input)
+}
+
+// Registry stores input-to-ID assignments. Expired entries are treated
+// as absent: they are neither returned nor in the way of registering
+// their ID anew.
+type Registry interface {
+	// Register assigns id to input in namespace, to expire after ttl if
+	// it is positive. Registering the same pair again is a no-op;
+	// registering an ID that belongs to a different input of the
+	// namespace fails with a *ConflictError.
+	Register(ctx context.Context, namespace, id, input string, ttl time.Duration) error
+	// Import registers e like Register, but keeps its CreatedAt and
+	// ExpiresAt, and reports whether it was added rather than already
+	// present.
+	Import(ctx context.Context, e Entry) (added bool, err error)
+	// Lookup returns the entries registered under id in namespace, if
+	// any.
+	Lookup(ctx context.Context, namespace, id string) ([]Entry, error)
+	// Walk calls fn for every entry of every namespace, in ascending
+	// order of namespace, then ID, and stops at the first error fn
+	// returns.
+	Walk(ctx context.Context, fn func(Entry) error) error
+	// Purge deletes the entries of all namespaces expired at now and
+	// returns how many.
+	Purge(ctx context.Context, now time.Time) (int, error)
+	// Ping checks that the underlying store is reachable.
+	Ping(ctx context.Context) error
+	// Close releases the underlying store.
+	Close() error
+}
+
+// Open opens the registry at path, creating it if necessary: a Redis
+// database if path is a redis:// or rediss:// URL, a bbolt database file
+// if path is bolt:FILE, else a SQLite database file.
+func Open(path string) (Registry, error) {
+	if strings.HasPrefix(path, "redis://") || strings.HasPrefix(path, "rediss://") {
+		return openRedis(path)
+	}
+	if file, ok := strings.CutPrefix(path, "bolt:"); ok {
+		return openBolt(file)
+	}
+	return openSQLite(path)
+}
+
+// storedEntry is the JSON value the key-value backends (Redis, bbolt)
+// store under an ID, in a key or bucket of its namespace. Times are Unix
+// seconds; ExpiresAt is 0 for entries that never expire.
+type storedEntry struct {
+	Input     string `json:"input"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+// encodeEntry encodes e as the storedEntry value of its ID.
+func encodeEntry(e Entry) ([]byte, error) {
+	return json.Marshal(storedEntry{Input: e.Input, CreatedAt: e.CreatedAt.Unix(), ExpiresAt: unixOrZero(e.ExpiresAt)})
+}
+
+// decodeEntry decodes the storedEntry value of id in namespace.
+func decodeEntry(namespace, id string, value []byte) (Entry, error) {
+	var e storedEntry
+	if err := json.Unmarshal(value, &e); err != nil {
+		return Entry{}, fmt.Errorf("decoding registry entry of %s: %w", id, err)
+	}
+	return Entry{Namespace: namespace, ID: id, Input: e.Input, CreatedAt: time.Unix(e.CreatedAt, 0), ExpiresAt: timeOrZero(e.ExpiresAt)}, nil
+}
+
+// unixOrZero returns t in Unix seconds, or 0 for the zero time.
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+// timeOrZero is the inverse of unixOrZero.
+func timeOrZero(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
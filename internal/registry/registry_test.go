@@ -0,0 +1,273 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package registry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// backend opens an empty registry of one kind for a test.
+type backend struct {
+	name string
+	open func(t *testing.T) Registry
+	// expiresItself is set for stores that delete expired entries on
+	// their own, leaving Purge nothing to do.
+	expiresItself bool
+}
+
+// backends returns the backends under test. Redis is tested only if
+// GOOFY_TEST_REDIS names the URL of a scratch database: its goofy keys
+// are deleted before every test.
+func backends() []backend {
+	return []backend{
+		{name: "sqlite", open: func(t *testing.T) Registry {
+			return openTest(t, filepath.Join(t.TempDir(), "ids.db"))
+		}},
+		{name: "bolt", open: func(t *testing.T) Registry {
+			return openTest(t, "bolt:"+filepath.Join(t.TempDir(), "ids.bolt"))
+		}},
+		{name: "redis", expiresItself: true, open: func(t *testing.T) Registry {
+			url := os.Getenv("GOOFY_TEST_REDIS")
+			if url == "" {
+				t.Skip("GOOFY_TEST_REDIS not set")
+			}
+			r := openTest(t, url)
+			client := r.(*redisRegistry).client
+			ctx := context.Background()
+			for _, pattern := range []string{redisKeyPrefix + "*", redisNamespacePrefix + "*"} {
+				iter := client.Scan(ctx, 0, pattern, 1000).Iterator()
+				for iter.Next(ctx) {
+					if err := client.Del(ctx, iter.Val()).Err(); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if err := iter.Err(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			return r
+		}},
+	}
+}
+
+// openTest opens the registry at path and closes it when t ends.
+func openTest(t *testing.T, path string) Registry {
+	t.Helper()
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := r.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return r
+}
+
+// conformance lists the behaviour every backend must share.
+var conformance = []struct {
+	name string
+	test func(t *testing.T, b backend, r Registry)
+}{
+	{"Conflicts", testConflicts},
+	{"Import", testImport},
+	{"Namespaces", testNamespaces},
+	{"Expiry", testExpiry},
+}
+
+func TestConformance(t *testing.T) {
+	for _, b := range backends() {
+		t.Run(b.name, func(t *testing.T) {
+			for _, c := range conformance {
+				t.Run(c.name, func(t *testing.T) {
+					r := b.open(t)
+					if err := r.Ping(context.Background()); err != nil {
+						t.Fatal(err)
+					}
+					c.test(t, b, r)
+				})
+			}
+		})
+	}
+}
+
+func testConflicts(t *testing.T, _ backend, r Registry) {
+	ctx := context.Background()
+	if err := r.Register(ctx, "", "0001", "a", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(ctx, "", "0001", "a", 0); err != nil {
+		t.Errorf("registering the same pair again: %v", err)
+	}
+	err := r.Register(ctx, "", "0001", "b", 0)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("registering another input under a taken ID: %v, want a *ConflictError", err)
+	}
+	want := ConflictError{ID: "0001", Input: "b", Existing: "a"}
+	if *conflict != want {
+		t.Errorf("conflict = %+v, want %+v", *conflict, want)
+	}
+	wantInputs(t, r, "", "0001", "a")
+	wantInputs(t, r, "", "0002")
+}
+
+func testImport(t *testing.T, _ backend, r Registry) {
+	ctx := context.Background()
+	created := time.Unix(1700000000, 0)
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	e := Entry{Namespace: "n", ID: "0001", Input: "a", CreatedAt: created, ExpiresAt: expires}
+	tests := []struct {
+		e         Entry
+		wantAdded bool
+		conflict  bool
+	}{
+		{e, true, false},
+		{e, false, false},
+		{Entry{Namespace: "n", ID: "0001", Input: "b", CreatedAt: created}, false, true},
+		{Entry{ID: "0001", Input: "b", CreatedAt: created}, true, false},
+	}
+	for i, tt := range tests {
+		added, err := r.Import(ctx, tt.e)
+		var conflict *ConflictError
+		if tt.conflict != errors.As(err, &conflict) || (err != nil && !tt.conflict) || added != tt.wantAdded {
+			t.Errorf("import %d of %+v = %v, %v, want %v with conflict %v", i+1, tt.e, added, err, tt.wantAdded, tt.conflict)
+		}
+	}
+	entries, err := r.Lookup(ctx, "n", "0001")
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Lookup = %v, %v, want one entry", entries, err)
+	}
+	if got := entries[0]; !got.CreatedAt.Equal(created) || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("imported entry has times %v and %v, want %v and %v", got.CreatedAt, got.ExpiresAt, created, expires)
+	}
+}
+
+func testNamespaces(t *testing.T, _ backend, r Registry) {
+	ctx := context.Background()
+	for _, e := range []Entry{
+		{Namespace: "b", ID: "0002", Input: "w"},
+		{Namespace: "", ID: "0003", Input: "x"},
+		{Namespace: "a", ID: "0001", Input: "y"},
+		{Namespace: "", ID: "0001", Input: "z"},
+		{Namespace: "a:b/c d", ID: "0001", Input: "v"},
+	} {
+		if err := r.Register(ctx, e.Namespace, e.ID, e.Input, 0); err != nil {
+			t.Fatalf("registering %+v: %v", e, err)
+		}
+	}
+	err := r.Register(ctx, "a", "0001", "z", 0)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.Namespace != "a" {
+		t.Errorf("conflict in namespace a: %v, want a *ConflictError naming the namespace", err)
+	}
+	wantInputs(t, r, "", "0001", "z")
+	wantInputs(t, r, "a", "0001", "y")
+	wantInputs(t, r, "a:b/c d", "0001", "v")
+	wantInputs(t, r, "b", "0001")
+	wantWalk(t, r, []Entry{
+		{Namespace: "", ID: "0001", Input: "z"},
+		{Namespace: "", ID: "0003", Input: "x"},
+		{Namespace: "a", ID: "0001", Input: "y"},
+		{Namespace: "a:b/c d", ID: "0001", Input: "v"},
+		{Namespace: "b", ID: "0002", Input: "w"},
+	})
+}
+
+func testExpiry(t *testing.T, b backend, r Registry) {
+	ctx := context.Background()
+	if err := r.Register(ctx, "", "0001", "short", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(ctx, "n", "0002", "short", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(ctx, "", "0003", "long", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(ctx, "", "0004", "forever", 0); err != nil {
+		t.Fatal(err)
+	}
+	wantInputs(t, r, "", "0001", "short")
+	time.Sleep(1100 * time.Millisecond)
+
+	wantInputs(t, r, "", "0001")
+	wantWalk(t, r, []Entry{
+		{ID: "0003", Input: "long"},
+		{ID: "0004", Input: "forever"},
+	})
+	want := 2
+	if b.expiresItself {
+		want = 0
+	}
+	if n, err := r.Purge(ctx, time.Now()); err != nil || n != want {
+		t.Errorf("Purge = %d, %v, want %d", n, err, want)
+	}
+	if n, err := r.Purge(ctx, time.Now()); err != nil || n != 0 {
+		t.Errorf("second Purge = %d, %v, want 0", n, err)
+	}
+	wantWalk(t, r, []Entry{
+		{ID: "0003", Input: "long"},
+		{ID: "0004", Input: "forever"},
+	})
+	// The lapsed ID is free for another input.
+	if err := r.Register(ctx, "", "0001", "other", 0); err != nil {
+		t.Errorf("registering an expired ID anew: %v", err)
+	}
+	wantInputs(t, r, "", "0001", "other")
+}
+
+// wantInputs checks that Lookup of id in namespace finds the entries of
+// inputs.
+func wantInputs(t *testing.T, r Registry, namespace, id string, inputs ...string) {
+	t.Helper()
+	entries, err := r.Lookup(context.Background(), namespace, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Input)
+	}
+	if !reflect.DeepEqual(got, inputs) {
+		t.Errorf("Lookup(%q, %q) found inputs %q, want %q", namespace, id, got, inputs)
+	}
+}
+
+// wantWalk checks that Walk visits the entries of want, in order,
+// ignoring their times.
+func wantWalk(t *testing.T, r Registry, want []Entry) {
+	t.Helper()
+	var got []Entry
+	err := r.Walk(context.Background(), func(e Entry) error {
+		got = append(got, Entry{Namespace: e.Namespace, ID: e.ID, Input: e.Input})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Walk = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,242 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS ids (
+	namespace  TEXT NOT NULL DEFAULT '',
+	id         TEXT NOT NULL,
+	input      TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	expires_at INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (namespace, id)
+)`
+
+// sqliteAddExpiry upgrades databases created before entries could expire.
+const sqliteAddExpiry = `ALTER TABLE ids ADD COLUMN expires_at INTEGER NOT NULL DEFAULT 0`
+
+// sqliteAddNamespace upgrades databases created before namespaces had ID
+// spaces of their own, moving their entries to the default namespace.
+// SQLite cannot change the primary key of a table, so it is rebuilt.
+var sqliteAddNamespace = []string{
+	`ALTER TABLE ids RENAME TO ids_old`,
+	sqliteSchema,
+	`INSERT INTO ids (id, input, created_at, expires_at) SELECT id, input, created_at, expires_at FROM ids_old`,
+	`DROP TABLE ids_old`,
+}
+
+// sqliteLive selects the entries that have not expired at the time given
+// as its parameter.
+const sqliteLive = `(expires_at = 0 OR expires_at > ?)`
+
+// sqliteBusyTimeout is how long SQLite retries statements while another
+// process writes to the database.
+const sqliteBusyTimeout = 5 * time.Second
+
+// sqliteRegistry is a Registry backed by a local SQLite database, which
+// several processes may share: registrations and purges hold an advisory
+// lock on the file PATH.lock, so that two processes cannot claim one ID
+// at once, and SQLite retries statements meeting another's write.
+type sqliteRegistry struct {
+	db   *sql.DB
+	lock *fileLock
+}
+
+func openSQLite(path string) (*sqliteRegistry, error) {
+	lock, err := openFileLock(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		lock.close()
+		return nil, err
+	}
+	// A single connection serializes access from this process.
+	db.SetMaxOpenConns(1)
+	r := &sqliteRegistry{db: db, lock: lock}
+	if err := r.init(); err != nil {
+		r.Close()
+		return nil, fmt.Errorf("initializing %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// init creates the schema of the database, or upgrades it.
+func (r *sqliteRegistry) init() error {
+	ctx := context.Background()
+	if err := r.lock.lock(ctx); err != nil {
+		return err
+	}
+	defer r.lock.unlock()
+
+	db := r.db
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA busy_timeout = %d", sqliteBusyTimeout.Milliseconds())); err != nil {
+		return err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+	hasExpiry, err := r.hasColumn("expires_at")
+	if err != nil {
+		return err
+	}
+	if !hasExpiry {
+		if _, err := db.Exec(sqliteAddExpiry); err != nil {
+			return err
+		}
+	}
+	hasNamespace, err := r.hasColumn("namespace")
+	if err != nil || hasNamespace {
+		return err
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, stmt := range sqliteAddNamespace {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// hasColumn reports whether the ids table has the named column.
+func (r *sqliteRegistry) hasColumn(name string) (bool, error) {
+	var has bool
+	err := r.db.QueryRow("SELECT COUNT(*) > 0 FROM pragma_table_info('ids') WHERE name = ?", name).Scan(&has)
+	return has, err
+}
+
+func (r *sqliteRegistry) Register(ctx context.Context, namespace, id, input string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := r.Import(ctx, Entry{Namespace: namespace, ID: id, Input: input, CreatedAt: now, ExpiresAt: expiry(now, ttl)})
+	return err
+}
+
+func (r *sqliteRegistry) Import(ctx context.Context, e Entry) (bool, error) {
+	if err := r.lock.lock(ctx); err != nil {
+		return false, err
+	}
+	defer r.lock.unlock()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	// An expired entry makes way for the new one.
+	_, err = tx.ExecContext(ctx, "DELETE FROM ids WHERE namespace = ? AND id = ? AND NOT "+sqliteLive, e.Namespace, e.ID, time.Now().Unix())
+	if err != nil {
+		return false, err
+	}
+	res, err := tx.ExecContext(ctx,
+		"INSERT OR IGNORE INTO ids (namespace, id, input, created_at, expires_at) VALUES (?, ?, ?, ?, ?)",
+		e.Namespace, e.ID, e.Input, e.CreatedAt.Unix(), unixOrZero(e.ExpiresAt))
+	if err != nil {
+		return false, err
+	}
+	added, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	var existing string
+	if err := tx.QueryRowContext(ctx, "SELECT input FROM ids WHERE namespace = ? AND id = ?", e.Namespace, e.ID).Scan(&existing); err != nil {
+		return false, err
+	}
+	if existing != e.Input {
+		return false, &ConflictError{Namespace: e.Namespace, ID: e.ID, Input: e.Input, Existing: existing}
+	}
+	return added > 0, tx.Commit()
+}
+
+func (r *sqliteRegistry) Lookup(ctx context.Context, namespace, id string) ([]Entry, error) {
+	var entries []Entry
+	err := r.query(ctx, func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	}, "SELECT namespace, id, input, created_at, expires_at FROM ids WHERE namespace = ? AND id = ? AND "+sqliteLive, namespace, id, time.Now().Unix())
+	return entries, err
+}
+
+func (r *sqliteRegistry) Walk(ctx context.Context, fn func(Entry) error) error {
+	return r.query(ctx, fn, "SELECT namespace, id, input, created_at, expires_at FROM ids WHERE "+sqliteLive+" ORDER BY namespace, id", time.Now().Unix())
+}
+
+func (r *sqliteRegistry) Purge(ctx context.Context, now time.Time) (int, error) {
+	if err := r.lock.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer r.lock.unlock()
+
+	res, err := r.db.ExecContext(ctx, "DELETE FROM ids WHERE NOT "+sqliteLive, now.Unix())
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+// query calls fn for each entry selected by the query, whose columns
+// must be namespace, id, input, created_at and expires_at.
+func (r *sqliteRegistry) query(ctx context.Context, fn func(Entry) error, query string, args ...any) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Entry
+		var created, expires int64
+		if err := rows.Scan(&e.Namespace, &e.ID, &e.Input, &created, &expires); err != nil {
+			return err
+		}
+		e.CreatedAt = time.Unix(created, 0)
+		e.ExpiresAt = timeOrZero(expires)
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *sqliteRegistry) Ping(ctx context.Context) error {
+	return r.db.PingContext(ctx)
+}
+
+func (r *sqliteRegistry) Close() error {
+	err := r.db.Close()
+	if lerr := r.lock.close(); err == nil {
+		err = lerr
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// jsonlCommand defines the flags of "goofy jsonl" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func jsonlCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	path := fs.String("path", "", "generate IDs for the field at dotted `PATH`, e.g. .user.email or .items.0.sku")
+	field := fs.String("id-field", "", "add the ID as top-level field `NAME` (default the last -path element with an _id suffix)")
+	maxRecord := fs.Int("max-record", defaultMaxRecord, "fail cleanly on lines longer than `N` bytes")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s jsonl [options] -path PATH [FILE...]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Copy JSON lines from the files (\"-\" for stdin, the default) to stdout,\n")
+		fmt.Fprintf(os.Stderr, "adding the plain ID of the string or number at -path to each object.\n")
+		fmt.Fprintf(os.Stderr, "The rest of each line is copied byte for byte, so field order and\n")
+		fmt.Fprintf(os.Stderr, "formatting are kept. Blank lines and objects without the field are\n")
+		fmt.Fprintf(os.Stderr, "passed through unchanged.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s jsonl -path .user.email events.jsonl\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  zcat events.jsonl.gz | %s jsonl -path .user.email -id-field user_code\n", os.Args[0])
+	}
+
+	return func() int {
+		opts, err := gen.options()
+		var keys []string
+		if err == nil {
+			keys, err = parsePath(*path)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		name := *field
+		if name == "" {
+			name = keys[len(keys)-1] + "_id"
+		}
+		files := fs.Args()
+		if len(files) == 0 {
+			files = []string{"-"}
+		}
+
+		w := bufio.NewWriter(os.Stdout)
+		a := annotator{keys: keys, path: *path, field: name, opts: opts}
+		for _, file := range files {
+			a.line = 0
+			err = processFile(file, inputOptions{maxRecord: *maxRecord}, func(line string) error {
+				a.line++
+				out, err := a.annotate(line)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", a.line, err)
+				}
+				w.WriteString(out)
+				return w.WriteByte('\n')
+			})
+			if err != nil {
+				break
+			}
+		}
+		if ferr := w.Flush(); err == nil {
+			err = ferr
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+}
+
+// parsePath splits a dotted -path such as ".user.email" into its keys.
+func parsePath(path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("missing required -path PATH")
+	}
+	keys := strings.Split(strings.TrimPrefix(path, "."), ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("invalid -path %q: empty element", path)
+		}
+	}
+	return keys, nil
+}
+
+// annotator adds the ID of the field at a path to JSON objects.
+type annotator struct {
+	keys  []string // the path, split
+	path  string   // the path as given, for errors
+	field string   // the top-level field the ID is added as
+	opts  goofy.Options
+	line  int
+}
+
+// annotate returns line with the ID field added before its closing brace,
+// or unchanged if it is blank or lacks the field.
+func (a *annotator) annotate(line string) (string, error) {
+	trimmed := strings.TrimRight(line, " \t\r")
+	if strings.TrimSpace(trimmed) == "" {
+		return line, nil
+	}
+	d := json.NewDecoder(strings.NewReader(trimmed))
+	d.UseNumber()
+	var obj map[string]any
+	if err := d.Decode(&obj); err != nil || obj == nil {
+		return "", fmt.Errorf("not a JSON object")
+	}
+	if d.More() {
+		return "", fmt.Errorf("trailing data after JSON object")
+	}
+	if _, ok := obj[a.field]; ok {
+		return "", fmt.Errorf("field %q already exists, pick another -id-field", a.field)
+	}
+
+	v, ok := lookupPath(obj, a.keys)
+	if !ok {
+		return line, nil
+	}
+	var input string
+	switch v := v.(type) {
+	case string:
+		input = v
+	case json.Number:
+		input = v.String()
+	default:
+		return "", fmt.Errorf("%s is not a string or number", a.path)
+	}
+
+	plain, err := goofy.TryGenerate(input, a.opts)
+	if err != nil {
+		return "", err
+	}
+	id, err := json.Marshal(plain)
+	if err != nil {
+		return "", err
+	}
+	name, err := json.Marshal(a.field)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	b.WriteString(strings.TrimRight(strings.TrimSuffix(trimmed, "}"), " \t\r\n"))
+	if len(obj) > 0 {
+		b.WriteByte(',')
+	}
+	b.Write(name)
+	b.WriteByte(':')
+	b.Write(id)
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// lookupPath returns the value at keys in v, indexing arrays with
+// numeric keys.
+func lookupPath(v any, keys []string) (any, bool) {
+	for _, k := range keys {
+		switch x := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = x[k]; !ok {
+				return nil, false
+			}
+		case []any:
+			i, err := strconv.Atoi(k)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, false
+			}
+			v = x[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,212 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// Messages are consumed and produced in batches of up to kafkaBatchSize,
+// collected for at most kafkaLinger after the first one arrives.
+const (
+	kafkaBatchSize = 100
+	kafkaLinger    = 50 * time.Millisecond
+)
+
+// kafkaTimeout bounds producing and committing a batch, which carries on
+// after a shutdown signal so that the batch in flight is not lost.
+const kafkaTimeout = 30 * time.Second
+
+// kafkaCommand defines the flags of "goofy kafka" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func kafkaCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	brokers := fs.String("brokers", "localhost:9092", "comma-separated Kafka broker `ADDRS`")
+	inTopic := fs.String("in-topic", "", "consume messages from `TOPIC`")
+	outTopic := fs.String("out-topic", "", "produce annotated messages to `TOPIC`")
+	group := fs.String("consumer-group", "goofy", "consumer group `ID` whose committed offsets consumption resumes from")
+	path := fs.String("path", "", "add the ID of the field at dotted `PATH` of JSON payloads as a field, like goofy jsonl")
+	field := fs.String("id-field", "", "with -path, add the ID as top-level field `NAME` (default the last -path element with an _id suffix)")
+	header := fs.String("header", "goofy-id", "without -path, add the ID of the whole payload as header `NAME`")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s kafka [options] -in-topic TOPIC -out-topic TOPIC\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Consume messages from -in-topic as part of -consumer-group and\n")
+		fmt.Fprintf(os.Stderr, "produce them to -out-topic with their key, headers and payload intact,\n")
+		fmt.Fprintf(os.Stderr, "annotated with an ID: the ID of the whole payload as a header, or with\n")
+		fmt.Fprintf(os.Stderr, "-path that of a JSON field as an added field. Whole payloads are hashed\n")
+		fmt.Fprintf(os.Stderr, "only up to -max-bytes; give -max-bytes 0 to hash them entirely.\n\n")
+		fmt.Fprintf(os.Stderr, "Offsets are committed once a batch has been produced, so messages are\n")
+		fmt.Fprintf(os.Stderr, "delivered at least once. On SIGINT or SIGTERM the batch in flight is\n")
+		fmt.Fprintf(os.Stderr, "finished before exiting. Payloads lacking the -path field, or not\n")
+		fmt.Fprintf(os.Stderr, "JSON objects, are passed through unchanged with a warning. A failing\n")
+		fmt.Fprintf(os.Stderr, "-algo exec: helper stops goofy with exit status 5, leaving the batch\n")
+		fmt.Fprintf(os.Stderr, "in flight uncommitted.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s kafka -in-topic raw -out-topic coded -max-bytes 0\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s kafka -brokers k1:9092,k2:9092 -in-topic events -out-topic events-coded -path .user.email\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		switch {
+		case err != nil:
+		case *inTopic == "" || *outTopic == "":
+			err = fmt.Errorf("missing required -in-topic and -out-topic")
+		case *inTopic == *outTopic:
+			err = fmt.Errorf("-in-topic and -out-topic must differ")
+		}
+		var a *annotator
+		if err == nil && *path != "" {
+			var keys []string
+			if keys, err = parsePath(*path); err == nil {
+				a = &annotator{keys: keys, path: *path, field: *field, opts: opts}
+				if a.field == "" {
+					a.field = keys[len(keys)-1] + "_id"
+				}
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		addrs := strings.Split(*brokers, ",")
+		r := kafka.NewReader(kafka.ReaderConfig{Brokers: addrs, GroupID: *group, Topic: *inTopic})
+		defer r.Close()
+		w := &kafka.Writer{
+			Addr:         kafka.TCP(addrs...),
+			Topic:        *outTopic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchSize:    kafkaBatchSize,
+			BatchTimeout: kafkaLinger,
+		}
+		defer w.Close()
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		slog.Info("consuming", "topic", *inTopic, "group", *group)
+		for {
+			batch, err := fetchBatch(ctx, r)
+			if ctx.Err() != nil && len(batch) == 0 {
+				return 0
+			}
+			if err != nil && len(batch) == 0 {
+				slog.Error("consuming", "topic", *inTopic, "err", err)
+				return 5
+			}
+			out := make([]kafka.Message, len(batch))
+			for i, m := range batch {
+				// The batch is left uncommitted, to be consumed again
+				// once the helper works.
+				if out[i], err = annotateMessage(m, a, *header, opts); err != nil {
+					slog.Error("generating IDs", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "err", err)
+					return 5
+				}
+			}
+			// The batch is finished even after a signal: its messages
+			// were consumed but are not yet committed.
+			wctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+			err = w.WriteMessages(wctx, out...)
+			if err == nil {
+				err = r.CommitMessages(wctx, batch...)
+			}
+			cancel()
+			if err != nil {
+				slog.Error("producing", "topic", *outTopic, "err", err)
+				return 5
+			}
+		}
+	}
+}
+
+// fetchBatch waits for a message from r, then collects up to
+// kafkaBatchSize-1 more that arrive within kafkaLinger.
+func fetchBatch(ctx context.Context, r *kafka.Reader) ([]kafka.Message, error) {
+	m, err := r.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch := []kafka.Message{m}
+	lctx, cancel := context.WithTimeout(ctx, kafkaLinger)
+	defer cancel()
+	for len(batch) < kafkaBatchSize {
+		m, err := r.FetchMessage(lctx)
+		if err != nil {
+			break
+		}
+		batch = append(batch, m)
+	}
+	return batch, nil
+}
+
+// annotateMessage returns m to be produced with its ID: with a the
+// payload annotated like goofy jsonl does, else with the ID of the whole
+// payload under opts as header. Messages a cannot annotate are passed
+// through unchanged; it fails only with the *goofy.ExecError of a failing
+// -algo exec: helper.
+func annotateMessage(m kafka.Message, a *annotator, header string, opts goofy.Options) (kafka.Message, error) {
+	out := kafka.Message{Key: m.Key, Value: m.Value, Headers: m.Headers}
+	if a == nil {
+		id, err := goofy.TryGenerate(string(m.Value), opts)
+		if err != nil {
+			return kafka.Message{}, err
+		}
+		out.Headers = append(slices.Clip(m.Headers), kafka.Header{Key: header, Value: []byte(id)})
+		return out, nil
+	}
+	value, err := a.annotate(string(m.Value))
+	var execErr *goofy.ExecError
+	if errors.As(err, &execErr) {
+		return kafka.Message{}, err
+	}
+	if err != nil {
+		slog.Warn("passing message through unchanged", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "err", err)
+		return out, nil
+	}
+	if value == string(m.Value) {
+		slog.Warn("message lacks the -path field, passing it through unchanged", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "path", a.path)
+	}
+	out.Value = []byte(value)
+	return out, nil
+}
@@ -0,0 +1,59 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// logFormats maps the -log-format names to the slog handlers writing
+// them to stderr.
+var logFormats = map[string]func(opts *slog.HandlerOptions) slog.Handler{
+	"text": func(opts *slog.HandlerOptions) slog.Handler { return slog.NewTextHandler(os.Stderr, opts) },
+	"json": func(opts *slog.HandlerOptions) slog.Handler { return slog.NewJSONHandler(os.Stderr, opts) },
+}
+
+// logFlags are the flags configuring the diagnostics logged on stderr,
+// such as warnings about truncated inputs and, in serve mode, the
+// server's errors. Every command has them.
+type logFlags struct {
+	level  slog.Level
+	format *string
+}
+
+// addLogFlags registers the log flags on fs.
+func addLogFlags(fs *flag.FlagSet) *logFlags {
+	l := &logFlags{}
+	fs.TextVar(&l.level, "log-level", slog.LevelInfo, "log messages at `LEVEL` and above: debug, info, warn, error")
+	l.format = fs.String("log-format", "text", "log `FORMAT`: text (key=value pairs) or json (one object per line)")
+	return l
+}
+
+// install makes the logger configured by the flags the default one.
+func (l *logFlags) install() error {
+	handler, ok := logFormats[*l.format]
+	if !ok {
+		return fmt.Errorf("unknown -log-format %q (want text or json)", *l.format)
+	}
+	slog.SetDefault(slog.New(handler(&slog.HandlerOptions{Level: l.level})))
+	return nil
+}
@@ -0,0 +1,119 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// metrics are the Prometheus collectors of a serve process, exposed on
+// GET /metrics.
+type metrics struct {
+	reg       *prometheus.Registry
+	requests  *prometheus.CounterVec   // by handler and status code
+	latency   *prometheus.HistogramVec // by handler
+	batchSize *prometheus.HistogramVec // inputs per batch, by handler
+	ids       prometheus.Counter
+	conflicts prometheus.Counter // -registry refusals of IDs owned by another input
+
+	keyRequests *prometheus.CounterVec // authenticated requests, by API key label
+}
+
+// newMetrics returns the serve metrics, registered together with the
+// standard Go runtime and process collectors.
+func newMetrics() *metrics {
+	m := &metrics{
+		reg: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goofy_requests_total",
+			Help: "Requests served, by handler and status code.",
+		}, []string{"handler", "code"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "goofy_request_duration_seconds",
+			Help:    "Request latencies, by handler.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"handler"}),
+		batchSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "goofy_batch_size",
+			Help:    "Inputs per batch request, by handler.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"handler"}),
+		ids: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "goofy_ids_generated_total",
+			Help: "IDs generated across all handlers.",
+		}),
+		conflicts: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "goofy_registry_conflicts_total",
+			Help: "IDs not handed out because -registry has them registered to another input.",
+		}),
+		keyRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goofy_authenticated_requests_total",
+			Help: "Requests accepted with an API key, by key label (-api-keys-file).",
+		}, []string{"key"}),
+	}
+	m.reg.MustRegister(
+		m.requests, m.latency, m.batchSize, m.ids, m.conflicts, m.keyRequests,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// handler serves the metrics in the Prometheus exposition format.
+func (m *metrics) handler() http.Handler {
+	return promhttp.HandlerFor(m.reg, promhttp.HandlerOpts{})
+}
+
+// instrument wraps the HTTP handler h, counting and timing its requests
+// under name.
+func (m *metrics) instrument(name string, h http.HandlerFunc) http.Handler {
+	labels := prometheus.Labels{"handler": name}
+	return promhttp.InstrumentHandlerCounter(m.requests.MustCurryWith(labels),
+		promhttp.InstrumentHandlerDuration(m.latency.MustCurryWith(labels), h))
+}
+
+// observe records a finished gRPC call to method.
+func (m *metrics) observe(method string, start time.Time, err error) {
+	m.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+	m.latency.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+// unaryInterceptor counts and times unary gRPC calls.
+func (m *metrics) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	m.observe(info.FullMethod, start, err)
+	return resp, err
+}
+
+// streamInterceptor counts and times streaming gRPC calls.
+func (m *metrics) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	m.observe(info.FullMethod, start, err)
+	return err
+}
@@ -0,0 +1,250 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// schemaNames names the types published under components/schemas in
+// /openapi.json; fields of these types refer to them instead of repeating
+// their schema.
+var schemaNames = map[reflect.Type]string{
+	reflect.TypeFor[record]():             "Record",
+	reflect.TypeFor[batchResult]():        "BatchResult",
+	reflect.TypeFor[batchResponse]():      "BatchResponse",
+	reflect.TypeFor[batchErrorResponse](): "BatchError",
+	reflect.TypeFor[batchV1Response]():    "BatchV1Response",
+	reflect.TypeFor[errorResponse]():      "Error",
+	reflect.TypeFor[healthResponse]():     "Health",
+}
+
+// namespaceParameter describes the namespace query parameter of the ID
+// endpoints.
+var namespaceParameter = map[string]any{
+	"name": "namespace", "in": "query",
+	"description": "The namespace to generate and register IDs in instead of the server's",
+	"schema":      map[string]any{"type": "string"},
+}
+
+// handleOpenAPI returns the handler of GET /openapi.json. The document is
+// built once, from the same types the handlers encode and decode.
+func (s *server) handleOpenAPI() http.HandlerFunc {
+	doc, err := json.MarshalIndent(s.openAPI(), "", "  ")
+	if err != nil {
+		panic(err) // the document holds nothing json cannot encode
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(doc)
+	}
+}
+
+// openAPI returns the OpenAPI 3 document describing the routes of s.
+func (s *server) openAPI() map[string]any {
+	schemas := map[string]any{}
+	for t, name := range schemaNames {
+		schemas[name] = structSchema(t)
+	}
+	inputs := map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	v1Inputs := map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "maxItems": s.maxBatch}
+	id := operation("generateID", "ID of a single string", nil,
+		map[string]any{"200": response("The ID", schemaOf(reflect.TypeFor[record]()))},
+		http.StatusBadRequest, http.StatusConflict, http.StatusTooManyRequests, http.StatusServiceUnavailable)
+	id["parameters"] = []any{map[string]any{
+		"name": "s", "in": "query", "required": true,
+		"description": "The string to derive the ID from",
+		"schema":      map[string]any{"type": "string"},
+	}, namespaceParameter}
+	batch := operation("generateBatch", "IDs of a JSON array of strings",
+		map[string]any{"required": true, "content": jsonContent(inputs)},
+		map[string]any{"200": response("One record per input, in order", schemaOf(reflect.TypeFor[batchResponse]()))},
+		http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusTooManyRequests)
+	for _, status := range []int{http.StatusConflict, http.StatusServiceUnavailable} {
+		batch["responses"].(map[string]any)[fmt.Sprint(status)] = response(
+			http.StatusText(status)+" on an input; the inputs before it are registered",
+			schemaOf(reflect.TypeFor[batchErrorResponse]()))
+	}
+	batch["parameters"] = []any{namespaceParameter}
+	batchV1 := operation("generateBatchV1",
+		"IDs of a JSON array or NDJSON stream of strings, with an error in place of each input that is not a string",
+		map[string]any{"required": true, "content": map[string]any{
+			"application/json":     map[string]any{"schema": v1Inputs},
+			"application/x-ndjson": map[string]any{"schema": map[string]any{"type": "string", "description": "One JSON string per line"}},
+		}},
+		map[string]any{"200": response("One result per input, in order", schemaOf(reflect.TypeFor[batchV1Response]()))},
+		http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusTooManyRequests)
+	batchV1["parameters"] = []any{namespaceParameter}
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "goofy",
+			"description": "Short, deterministic numeric IDs for strings.",
+			"version":     "1",
+		},
+		"paths": map[string]any{
+			"/id":       map[string]any{"get": id},
+			"/batch":    map[string]any{"post": batch},
+			"/v1/batch": map[string]any{"post": batchV1},
+			"/metrics": map[string]any{
+				"get": operation("metrics", "Prometheus metrics", nil, map[string]any{
+					"200": map[string]any{
+						"description": "Metrics in the Prometheus text format",
+						"content":     map[string]any{"text/plain": map[string]any{"schema": map[string]any{"type": "string"}}},
+					},
+				}),
+			},
+			"/healthz": map[string]any{
+				"get": operation("healthz", "Liveness probe", nil, map[string]any{
+					"200": response("The process is serving", schemaOf(reflect.TypeFor[healthResponse]())),
+				}),
+			},
+			"/readyz": map[string]any{
+				"get": operation("readyz", "Readiness probe", nil, map[string]any{
+					"200": response("ID requests can be answered", schemaOf(reflect.TypeFor[healthResponse]())),
+				}, http.StatusServiceUnavailable),
+			},
+			"/openapi.json": map[string]any{
+				"get": operation("openAPI", "This document", nil, map[string]any{
+					"200": response("The OpenAPI document", map[string]any{"type": "object"}),
+				}),
+			},
+		},
+		"components": map[string]any{"schemas": schemas},
+	}
+	if s.keys != nil {
+		s.secureOpenAPI(doc)
+	}
+	return doc
+}
+
+// secureOpenAPI declares in doc the API keys -api-keys-file requires on
+// every operation but the probes.
+func (s *server) secureOpenAPI(doc map[string]any) {
+	doc["components"].(map[string]any)["securitySchemes"] = map[string]any{
+		"apiKey": map[string]any{"type": "apiKey", "in": "header", "name": apiKeyHeader},
+		"bearer": map[string]any{"type": "http", "scheme": "bearer"},
+	}
+	doc["security"] = []any{map[string]any{"apiKey": []any{}}, map[string]any{"bearer": []any{}}}
+	for path, item := range doc["paths"].(map[string]any) {
+		for _, op := range item.(map[string]any) {
+			op := op.(map[string]any)
+			if path == "/healthz" || path == "/readyz" {
+				op["security"] = []any{}
+				continue
+			}
+			op["responses"].(map[string]any)["401"] = response(http.StatusText(http.StatusUnauthorized), schemaOf(reflect.TypeFor[errorResponse]()))
+		}
+	}
+}
+
+// operation returns an OpenAPI operation object; statuses lists those
+// answered with an Error body besides 405.
+func operation(id, summary string, body, responses map[string]any, statuses ...int) map[string]any {
+	op := map[string]any{"operationId": id, "summary": summary, "responses": responses}
+	if body != nil {
+		op["requestBody"] = body
+	}
+	for _, status := range append(statuses, http.StatusMethodNotAllowed) {
+		responses[fmt.Sprint(status)] = response(http.StatusText(status), schemaOf(reflect.TypeFor[errorResponse]()))
+	}
+	return op
+}
+
+// response returns an OpenAPI response object with a JSON body.
+func response(description string, schema map[string]any) map[string]any {
+	return map[string]any{"description": description, "content": jsonContent(schema)}
+}
+
+// jsonContent returns an OpenAPI content map for a JSON body.
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaOf returns the JSON schema of values of t as encoding/json writes
+// them, referring to the named types of schemaNames.
+func schemaOf(t reflect.Type) map[string]any {
+	if name, ok := schemaNames[t]; ok {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem())
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	panic("openapi: no schema for " + t.String())
+}
+
+// structSchema returns the object schema of struct type t. Fields tagged
+// omitempty and those promoted from embedded pointers, which may be nil,
+// are optional.
+func structSchema(t reflect.Type) map[string]any {
+	props := map[string]any{}
+	var required []string
+	var walk func(t reflect.Type, optional bool)
+	walk = func(t reflect.Type, optional bool) {
+		for f := range t.Fields() {
+			if f.Anonymous {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					walk(ft.Elem(), true)
+				} else {
+					walk(ft, optional)
+				}
+				continue
+			}
+			tag := f.Tag.Get("json")
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if name == "" {
+				name = f.Name
+			}
+			props[name] = schemaOf(f.Type)
+			if !optional && !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	walk(t, false)
+	schema := map[string]any{"type": "object", "properties": props}
+	if required != nil {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,495 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// record is one generated ID together with what it was derived from.
+type record struct {
+	Input     string `json:"input"`
+	ID        string `json:"id"`
+	Namespace string `json:"namespace,omitempty"`
+	Counter   *int   `json:"counter,omitempty"`   // the alternative, with -alts
+	Nonce     *int   `json:"nonce,omitempty"`     // the counter giving -require-prefix
+	Digest    string `json:"digest,omitempty"`    // full hex digest, for Digester algorithms
+	Truncated bool   `json:"truncated,omitempty"` // only a prefix of Input was hashed
+	// data is what the ID was derived from: Input itself, or the resource
+	// fetched for it with -url.
+	data string
+	// sum and value are the hash of data and the number the ID encodes,
+	// kept for the formats writing those instead of the ID.
+	sum, value uint64
+	// err, if set, replaces the record: its data could not be loaded.
+	err error
+}
+
+// recordError reports a problem with a single input as a JSON object with
+// -output json, so that pipelines can handle partial failures.
+type recordError struct {
+	Error   string `json:"error"` // truncated, collision or load
+	Input   string `json:"input"`
+	ID      string `json:"id,omitempty"`      // collision: the shared ID
+	First   string `json:"first,omitempty"`   // collision: the input that had the ID first
+	Message string `json:"message,omitempty"` // load: why loading failed
+}
+
+// options returns opts adjusted to the alternative rec is, if any.
+func (rec record) options(opts goofy.Options) goofy.Options {
+	if rec.Counter != nil {
+		opts.Counter = *rec.Counter
+	}
+	if rec.Nonce != nil {
+		opts.Counter = *rec.Nonce
+	}
+	return opts
+}
+
+// newRecord returns the record for input generated under opts. It fails
+// with the *goofy.ExecError of a failing -algo exec: helper.
+func newRecord(input string, opts goofy.Options) (rec record, err error) {
+	_, truncated := goofy.HashedInput(input, opts)
+	err = goofy.Catch(func() {
+		sum := goofy.Sum64(input, opts)
+		rec = record{
+			Input:     input,
+			ID:        goofy.GenerateFromSum64(sum, opts),
+			Namespace: opts.Namespace,
+			Digest:    hex.EncodeToString(goofy.Digest(input, opts)),
+			Truncated: truncated,
+			data:      input,
+			sum:       sum,
+			value:     goofy.ValueFromSum64(sum, opts),
+		}
+	})
+	return rec, err
+}
+
+// recordWriter renders records in a particular output format.
+type recordWriter interface {
+	Write(rec record) error
+	Flush() error
+}
+
+// outputOptions tunes the record writers.
+type outputOptions struct {
+	echo      bool   // text: prefix each ID with its input
+	nul       bool   // text: terminate records with NUL instead of newline
+	color     bool   // text: highlight IDs and dim echoed inputs
+	namespace bool   // csv: add a namespace column
+	counter   bool   // csv: add a counter column
+	nonce     bool   // text, csv, sql: add the -require-prefix nonce
+	digest    bool   // csv: add a digest column
+	table     string // sql: the table to insert into
+	check     bool   // crockford32: append the check symbol
+	// opts are the options records were generated with, which size the
+	// hash formats (see hashWriter).
+	opts goofy.Options
+}
+
+// hasDigest reports whether records generated under opts carry a digest.
+func hasDigest(opts goofy.Options) bool {
+	h, err := goofy.NewHasher(opts.Algo, opts.Key)
+	if err != nil {
+		return false
+	}
+	_, ok := h.(goofy.Digester)
+	return ok
+}
+
+// newRecordWriter returns the recordWriter for the named output format.
+func newRecordWriter(format string, w io.Writer, o outputOptions) (recordWriter, error) {
+	switch format {
+	case "text":
+		tw := &textWriter{w: bufio.NewWriter(w), echo: o.echo, nonce: o.nonce, color: o.color, term: '\n'}
+		if o.nul {
+			tw.term = 0
+		}
+		return tw, nil
+	case "csv":
+		return newCSVWriter(w, o.namespace, o.counter, o.nonce, o.digest)
+	case "json":
+		bw := bufio.NewWriter(w)
+		return &jsonWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case "sql":
+		return newSQLWriter(w, o.table, o.namespace, o.counter, o.nonce, o.digest)
+	case "hex", "raw64", "crockford32", "proquint", "emoji", "bubblebabble":
+		if format == "emoji" && o.opts.Digits > goofy.MaxEmoji {
+			return nil, fmt.Errorf("emoji output takes at most %d emoji, got -digits %d", goofy.MaxEmoji, o.opts.Digits)
+		}
+		tw, _ := newRecordWriter("text", w, o)
+		return &hashWriter{textWriter: tw.(*textWriter), opts: o.opts, format: format, check: o.check}, nil
+	case "detail":
+		return &detailWriter{w: bufio.NewWriter(w), opts: o.opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// textWriter writes one ID per record, optionally preceded by its input.
+type textWriter struct {
+	w     *bufio.Writer
+	echo  bool // prefix each ID with its input and a tab
+	nonce bool // follow each ID with a tab and its -require-prefix nonce
+	color bool // wrap inputs and IDs in ANSI escapes
+	term  byte // record terminator
+}
+
+func (t *textWriter) Write(rec record) error {
+	if t.echo {
+		t.paint(ansiInput, rec.Input)
+		t.w.WriteByte('\t')
+	}
+	t.paint(ansiID, rec.ID)
+	if t.nonce && rec.Nonce != nil {
+		t.w.WriteByte('\t')
+		t.w.WriteString(strconv.Itoa(*rec.Nonce))
+	}
+	return t.w.WriteByte(t.term)
+}
+
+// paint writes s, in the color selected by the ANSI escape esc if
+// coloring is on.
+func (t *textWriter) paint(esc, s string) {
+	if !t.color {
+		t.w.WriteString(s)
+		return
+	}
+	t.w.WriteString(esc)
+	t.w.WriteString(s)
+	t.w.WriteString(ansiReset)
+}
+
+func (t *textWriter) Flush() error {
+	return t.w.Flush()
+}
+
+// hashWriter writes the hash behind each ID instead of the ID, like
+// textWriter otherwise: with format hex the value the ID encodes in
+// lowercase hex, with raw64 the full 64-bit hash in decimal, with
+// crockford32 the hash in -digits symbols of Crockford's Base32, with
+// proquint its low 32 bits as two pronounceable quintets, with emoji the
+// hash in -digits emoji, and with bubblebabble the full hash (big-endian)
+// in Bubble Babble.
+type hashWriter struct {
+	*textWriter
+	opts   goofy.Options
+	format string
+	check  bool // crockford32: append the check symbol
+}
+
+func (h *hashWriter) Write(rec record) error {
+	opts := rec.options(h.opts)
+	switch h.format {
+	case "hex":
+		rec.ID = strconv.FormatUint(rec.value, 16)
+	case "raw64":
+		rec.ID = strconv.FormatUint(rec.sum, 10)
+	case "crockford32":
+		rec.ID = goofy.Crockford32(rec.sum, cmp.Or(opts.Digits, goofy.DefaultDigits), h.check)
+	case "proquint":
+		rec.ID = goofy.Proquint(rec.sum, 2)
+	case "emoji":
+		rec.ID = goofy.Emoji(rec.sum, cmp.Or(opts.Digits, goofy.DefaultDigits))
+	case "bubblebabble":
+		rec.ID = goofy.BubbleBabble(binary.BigEndian.AppendUint64(nil, rec.sum))
+	}
+	return h.textWriter.Write(rec)
+}
+
+// detailWriter writes each ID in a block of labelled lines together with
+// what went into it: the algorithm, the ID format, whether a salt or key
+// was mixed in, the namespace, the bytes hashed and the full 64-bit hash,
+// so that the output of two deployments that disagree can be compared.
+// Secrets are only reported as present. Blocks are separated by a blank
+// line.
+type detailWriter struct {
+	w     *bufio.Writer
+	opts  goofy.Options
+	count int
+}
+
+func (d *detailWriter) Write(rec record) error {
+	opts := rec.options(d.opts)
+	if d.count++; d.count > 1 {
+		d.w.WriteByte('\n')
+	}
+	line := func(label, value string) {
+		fmt.Fprintf(d.w, "%-10s %s\n", label+":", value)
+	}
+	line("id", rec.ID)
+	line("input", strconv.Quote(rec.Input))
+	line("algo", cmp.Or(opts.Algo, goofy.DefaultAlgo))
+	digits := cmp.Or(opts.Digits, goofy.DefaultDigits)
+	switch {
+	case opts.Words != 0:
+		line("format", fmt.Sprintf("%d words and a number", opts.Words))
+	case opts.Alphabet != "":
+		line("format", fmt.Sprintf("%d symbols of %s", digits, opts.Alphabet))
+	default:
+		line("format", fmt.Sprintf("%d digits of base %d", digits, cmp.Or(opts.Base, goofy.DefaultBase)))
+	}
+	if opts.CheckDigit != "" {
+		line("check", opts.CheckDigit)
+	}
+	line("salt", yesNo(opts.Salt != ""))
+	line("key", yesNo(len(opts.Key) > 0))
+	line("namespace", cmp.Or(opts.Namespace, "-"))
+	if opts.Construction > 1 {
+		line("message", fmt.Sprintf("construction %d", opts.Construction))
+	}
+	if opts.Range != nil {
+		line("range", opts.Range.String())
+	}
+	if opts.Rotate != "" {
+		at := opts.At
+		if at.IsZero() {
+			at = time.Now()
+		}
+		period, _ := goofy.Period(opts.Rotate, at)
+		line("period", period)
+	}
+	if rec.Counter != nil || rec.Nonce != nil {
+		line("counter", strconv.Itoa(opts.Counter))
+	}
+	hashed, truncated := goofy.HashedInput(rec.data, opts)
+	line("hashed", fmt.Sprintf("%d of %d bytes (message %d bytes)", len(hashed), len(goofy.Preprocess(rec.data, opts)), len(goofy.Message(rec.data, opts))))
+	line("truncated", yesNo(truncated))
+	line("hash", fmt.Sprintf("%d (0x%016x)", rec.sum, rec.sum))
+	line("value", strconv.FormatUint(rec.value, 10))
+	return nil
+}
+
+func (d *detailWriter) Flush() error {
+	return d.w.Flush()
+}
+
+// yesNo renders b for humans.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// csvWriter writes an "input,id,truncated" header followed by one row per
+// record, quoting fields as needed. With namespace set an extra column
+// after id carries the record's namespace, with counter set one after
+// that carries its alternative counter, with nonce set one after that
+// carries its -require-prefix nonce, and with digest set one after that
+// carries its full digest.
+type csvWriter struct {
+	w         *csv.Writer
+	namespace bool
+	counter   bool
+	nonce     bool
+	digest    bool
+}
+
+func newCSVWriter(w io.Writer, namespace, counter, nonce, digest bool) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), namespace: namespace, counter: counter, nonce: nonce, digest: digest}
+	header := []string{"input", "id"}
+	if namespace {
+		header = append(header, "namespace")
+	}
+	if counter {
+		header = append(header, "counter")
+	}
+	if nonce {
+		header = append(header, "nonce")
+	}
+	if digest {
+		header = append(header, "digest")
+	}
+	header = append(header, "truncated")
+	if err := cw.w.Write(header); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) Write(rec record) error {
+	row := []string{rec.Input, rec.ID}
+	if c.namespace {
+		row = append(row, rec.Namespace)
+	}
+	if c.counter {
+		row = append(row, strconv.Itoa(rec.options(goofy.Options{}).Counter))
+	}
+	if c.nonce {
+		row = append(row, strconv.Itoa(rec.options(goofy.Options{}).Counter))
+	}
+	if c.digest {
+		row = append(row, rec.Digest)
+	}
+	row = append(row, strconv.FormatBool(rec.Truncated))
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// sqlIdentifier matches the table names -output sql accepts: unquoted,
+// optionally schema-qualified SQL identifiers, which need no escaping.
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// sqlWriter writes one "INSERT INTO table (input, id) VALUES (...);"
+// statement per record, with the namespace, counter, nonce and digest columns
+// of csvWriter as selected. Strings are written as standard SQL literals
+// with quotes doubled, so any input loads safely.
+type sqlWriter struct {
+	w         *bufio.Writer
+	prefix    string // the statement up to VALUES
+	namespace bool
+	counter   bool
+	nonce     bool
+	digest    bool
+}
+
+func newSQLWriter(w io.Writer, table string, namespace, counter, nonce, digest bool) (*sqlWriter, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid -table %q: want a plain SQL identifier such as codes or public.codes", table)
+	}
+	columns := []string{"input", "id"}
+	if namespace {
+		columns = append(columns, "namespace")
+	}
+	if counter {
+		columns = append(columns, "counter")
+	}
+	if nonce {
+		columns = append(columns, "nonce")
+	}
+	if digest {
+		columns = append(columns, "digest")
+	}
+	return &sqlWriter{
+		w:         bufio.NewWriter(w),
+		prefix:    "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ") VALUES (",
+		namespace: namespace,
+		counter:   counter,
+		nonce:     nonce,
+		digest:    digest,
+	}, nil
+}
+
+func (s *sqlWriter) Write(rec record) error {
+	if strings.IndexByte(rec.Input, 0) >= 0 || !utf8.ValidString(rec.Input) {
+		return fmt.Errorf("input %q cannot be stored as SQL text: it contains NUL or invalid UTF-8", rec.Input)
+	}
+	s.w.WriteString(s.prefix)
+	s.literal(rec.Input)
+	s.w.WriteString(", ")
+	s.literal(rec.ID)
+	if s.namespace {
+		s.w.WriteString(", ")
+		s.literal(rec.Namespace)
+	}
+	if s.counter {
+		s.w.WriteString(", ")
+		s.w.WriteString(strconv.Itoa(rec.options(goofy.Options{}).Counter))
+	}
+	if s.nonce {
+		s.w.WriteString(", ")
+		s.w.WriteString(strconv.Itoa(rec.options(goofy.Options{}).Counter))
+	}
+	if s.digest {
+		s.w.WriteString(", ")
+		s.literal(rec.Digest)
+	}
+	_, err := s.w.WriteString(");\n")
+	return err
+}
+
+// literal writes v as an SQL string literal.
+func (s *sqlWriter) literal(v string) {
+	s.w.WriteByte('\'')
+	s.w.WriteString(strings.ReplaceAll(v, "'", "''"))
+	s.w.WriteByte('\'')
+}
+
+func (s *sqlWriter) Flush() error {
+	return s.w.Flush()
+}
+
+// sortWriter buffers records and writes them to out sorted by input or
+// ID when flushed, keeping records with the same key in order.
+type sortWriter struct {
+	out  recordWriter
+	byID bool
+	recs []record
+}
+
+func (s *sortWriter) Write(rec record) error {
+	s.recs = append(s.recs, rec)
+	return nil
+}
+
+func (s *sortWriter) Flush() error {
+	slices.SortStableFunc(s.recs, func(a, b record) int {
+		if s.byID {
+			return strings.Compare(a.ID, b.ID)
+		}
+		return strings.Compare(a.Input, b.Input)
+	})
+	for _, rec := range s.recs {
+		if err := s.out.Write(rec); err != nil {
+			return err
+		}
+	}
+	s.recs = nil
+	return s.out.Flush()
+}
+
+// jsonWriter writes one JSON object per record (JSON Lines).
+type jsonWriter struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (j *jsonWriter) Write(rec record) error {
+	return j.enc.Encode(rec)
+}
+
+// WriteError writes e as a JSON object of its own.
+func (j *jsonWriter) WriteError(e recordError) error {
+	return j.enc.Encode(e)
+}
+
+func (j *jsonWriter) Flush() error {
+	return j.w.Flush()
+}
@@ -0,0 +1,91 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// confusables groups characters that are easily mistaken for one another
+// when read or written down; a custom alphabet may hold at most one of
+// each group. Upper and lower case letters that differ only in size count
+// as confusable, too.
+var confusables = []string{
+	"0Oo", "1IiLl|!", "cC", "kK", "pP", "sS", "uU", "vV", "wW", "xX", "zZ",
+	"'`", ",.", ":;",
+}
+
+// checkAlphabet reports whether alphabet can render IDs: at least two
+// distinct printable ASCII characters other than space, none of them
+// confusable with another.
+func checkAlphabet(alphabet string) error {
+	if len(alphabet) < 2 {
+		return fmt.Errorf("alphabet %q must have at least 2 symbols", alphabet)
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c <= ' ' || c > '~' {
+			return fmt.Errorf("alphabet %q must consist of printable ASCII characters other than space", alphabet)
+		}
+		if strings.IndexByte(alphabet[i+1:], c) >= 0 {
+			return fmt.Errorf("alphabet %q repeats %q", alphabet, c)
+		}
+	}
+	for _, group := range confusables {
+		first := -1
+		for i := 0; i < len(group); i++ {
+			if !strings.Contains(alphabet, group[i:i+1]) {
+				continue
+			}
+			if first >= 0 {
+				return fmt.Errorf("alphabet %q holds the confusable characters %q and %q", alphabet, group[first], group[i])
+			}
+			first = i
+		}
+	}
+	return nil
+}
+
+// alphabet returns the symbols IDs are rendered with: opts.Alphabet if
+// set, or those of the effective base.
+func (opts Options) alphabet() string {
+	if opts.Alphabet != "" {
+		return opts.Alphabet
+	}
+	return alphabets[opts.base()]
+}
+
+// DigitsFor returns the ID length in symbols of the alphabet selected by
+// opts (Alphabet or Base) that carries at least entropy bits, but no less
+// than MinDigits. Lengths beyond MaxDigits or the 64-bit hash fail
+// Validate.
+func (opts Options) DigitsFor(entropy int) int {
+	size := uint64(len(opts.alphabet()))
+	if size < 2 {
+		return MinDigits // invalid, left to Validate
+	}
+	n, hi, p := 0, uint64(0), uint64(1)
+	for hi == 0 && (entropy >= 64 || p>>entropy == 0) {
+		hi, p = bits.Mul64(p, size)
+		n++
+	}
+	return max(n, MinDigits)
+}
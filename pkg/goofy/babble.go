@@ -0,0 +1,53 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+// Bubble Babble vowels and consonants; the last consonant, x, only marks
+// the start and the end.
+const (
+	babbleVowels     = "aeiouy"
+	babbleConsonants = "bcdfghklmnprstvzx"
+)
+
+// BubbleBabble returns the Bubble Babble encoding of data, the
+// pronounceable format ssh-keygen -B prints key fingerprints in, e.g.
+// "xesef-disof-gytuf-katof-movif-baxux" for "1234567890". Every two bytes
+// become a five-letter group, with a checksum carried through the vowels.
+func BubbleBabble(data []byte) string {
+	out := []byte{'x'}
+	seed := 1
+	rounds := len(data)/2 + 1
+	for i := 0; i < rounds; i++ {
+		if i+1 < rounds || len(data)%2 != 0 {
+			b1 := int(data[2*i])
+			out = append(out,
+				babbleVowels[(b1>>6&3+seed)%6],
+				babbleConsonants[b1>>2&15],
+				babbleVowels[(b1&3+seed/6)%6])
+			if i+1 < rounds {
+				b2 := int(data[2*i+1])
+				out = append(out, babbleConsonants[b2>>4&15], '-', babbleConsonants[b2&15])
+				seed = (seed*5 + b1*7 + b2) % 36
+			}
+		} else {
+			out = append(out, babbleVowels[seed%6], 'x', babbleVowels[seed/6])
+		}
+	}
+	return string(append(out, 'x'))
+}
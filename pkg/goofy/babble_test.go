@@ -0,0 +1,33 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package goofy
+
+import "testing"
+
+func TestBubbleBabble(t *testing.T) {
+	// The test vectors of the Bubble Babble specification.
+	tests := []struct{ data, want string }{
+		{"", "xexax"},
+		{"1234567890", "xesef-disof-gytuf-katof-movif-baxux"},
+		{"Pineapple", "xigak-nyryk-humil-bosek-sonax"},
+	}
+	for _, tt := range tests {
+		if got := BubbleBabble([]byte(tt.data)); got != tt.want {
+			t.Errorf("BubbleBabble(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
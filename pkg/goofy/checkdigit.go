@@ -0,0 +1,161 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// checkDigitSchemes maps check digit scheme names to functions computing
+// the check digit of a string of decimal digits.
+var checkDigitSchemes = map[string]func(digits string) byte{
+	"luhn":     luhn,
+	"damm":     damm,
+	"verhoeff": verhoeff,
+}
+
+// CheckDigitSchemes returns the names of the supported check digit
+// schemes, sorted.
+func CheckDigitSchemes() []string {
+	names := make([]string, 0, len(checkDigitSchemes))
+	for name := range checkDigitSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckDigit returns the check digit of digits under the named scheme.
+func CheckDigit(scheme, digits string) (byte, error) {
+	fn, ok := checkDigitSchemes[scheme]
+	if !ok {
+		return 0, fmt.Errorf("unknown check digit scheme %q", scheme)
+	}
+	if !isDecimal(digits) {
+		return 0, fmt.Errorf("check digits require a decimal ID, got %q", digits)
+	}
+	return fn(digits), nil
+}
+
+// ValidCheckDigit reports whether the last digit of id is the correct
+// check digit for the rest of it under the named scheme. Spaces in id
+// are ignored.
+func ValidCheckDigit(scheme, id string) (bool, error) {
+	id = strings.ReplaceAll(id, " ", "")
+	if len(id) < 2 {
+		return false, fmt.Errorf("ID %q is too short to carry a check digit", id)
+	}
+	want, err := CheckDigit(scheme, id[:len(id)-1])
+	if err != nil {
+		return false, err
+	}
+	return id[len(id)-1] == want, nil
+}
+
+// isDecimal reports whether s consists of decimal digits only.
+func isDecimal(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// luhn returns the Luhn (mod 10) check digit of digits.
+func luhn(digits string) byte {
+	sum := 0
+	double := true // the rightmost payload digit is doubled
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// dammTable is the order-10 totally anti-symmetric quasigroup used by the
+// Damm algorithm.
+var dammTable = [10][10]byte{
+	{0, 3, 1, 7, 5, 9, 8, 6, 4, 2},
+	{7, 0, 9, 2, 1, 5, 4, 8, 6, 3},
+	{4, 2, 0, 6, 8, 7, 1, 3, 5, 9},
+	{1, 7, 5, 0, 9, 8, 3, 4, 2, 6},
+	{6, 1, 2, 3, 0, 4, 5, 9, 7, 8},
+	{3, 6, 7, 4, 2, 0, 9, 5, 8, 1},
+	{5, 8, 6, 9, 7, 2, 0, 1, 3, 4},
+	{8, 9, 4, 5, 3, 6, 2, 0, 1, 7},
+	{9, 4, 3, 8, 6, 1, 7, 2, 0, 5},
+	{2, 5, 8, 1, 4, 3, 6, 7, 9, 0},
+}
+
+// damm returns the Damm check digit of digits.
+func damm(digits string) byte {
+	var interim byte
+	for i := 0; i < len(digits); i++ {
+		interim = dammTable[interim][digits[i]-'0']
+	}
+	return '0' + interim
+}
+
+// Verhoeff multiplication (dihedral group D5), permutation and inverse
+// tables.
+var (
+	verhoeffD = [10][10]byte{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 2, 3, 4, 0, 6, 7, 8, 9, 5},
+		{2, 3, 4, 0, 1, 7, 8, 9, 5, 6},
+		{3, 4, 0, 1, 2, 8, 9, 5, 6, 7},
+		{4, 0, 1, 2, 3, 9, 5, 6, 7, 8},
+		{5, 9, 8, 7, 6, 0, 4, 3, 2, 1},
+		{6, 5, 9, 8, 7, 1, 0, 4, 3, 2},
+		{7, 6, 5, 9, 8, 2, 1, 0, 4, 3},
+		{8, 7, 6, 5, 9, 3, 2, 1, 0, 4},
+		{9, 8, 7, 6, 5, 4, 3, 2, 1, 0},
+	}
+	verhoeffP = [8][10]byte{
+		{0, 1, 2, 3, 4, 5, 6, 7, 8, 9},
+		{1, 5, 7, 6, 2, 8, 3, 0, 9, 4},
+		{5, 8, 0, 3, 7, 9, 6, 1, 4, 2},
+		{8, 9, 1, 6, 0, 4, 3, 5, 2, 7},
+		{9, 4, 5, 3, 1, 2, 7, 6, 8, 0},
+		{4, 2, 8, 6, 5, 7, 3, 9, 0, 1},
+		{2, 7, 9, 3, 8, 0, 6, 4, 1, 5},
+		{7, 0, 4, 6, 9, 1, 3, 2, 5, 8},
+	}
+	verhoeffInv = [10]byte{0, 4, 3, 2, 1, 5, 6, 7, 8, 9}
+)
+
+// verhoeff returns the Verhoeff check digit of digits.
+func verhoeff(digits string) byte {
+	var c byte
+	for i := 0; i < len(digits); i++ {
+		d := digits[len(digits)-1-i] - '0'
+		c = verhoeffD[c][verhoeffP[(i+1)%8][d]]
+	}
+	return '0' + verhoeffInv[c]
+}
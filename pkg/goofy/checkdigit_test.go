@@ -0,0 +1,80 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package goofy
+
+import "testing"
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		scheme, digits string
+		want           byte
+	}{
+		{"luhn", "7992739871", '3'},
+		{"luhn", "0", '0'},
+		{"luhn", "4539148803436", '4'},
+		{"damm", "572", '4'},
+		{"damm", "5724", '0'},
+		{"damm", "0", '0'},
+		{"verhoeff", "236", '3'},
+		{"verhoeff", "12345", '1'},
+		{"verhoeff", "142857", '0'},
+	}
+	for _, tt := range tests {
+		got, err := CheckDigit(tt.scheme, tt.digits)
+		if err != nil || got != tt.want {
+			t.Errorf("CheckDigit(%q, %q) = %q, %v, want %q", tt.scheme, tt.digits, got, err, tt.want)
+		}
+	}
+}
+
+func TestCheckDigitErrors(t *testing.T) {
+	tests := []struct{ scheme, digits string }{
+		{"mod11", "123"},
+		{"luhn", "12a"},
+		{"damm", "1 2"},
+	}
+	for _, tt := range tests {
+		if got, err := CheckDigit(tt.scheme, tt.digits); err == nil {
+			t.Errorf("CheckDigit(%q, %q) = %q, want an error", tt.scheme, tt.digits, got)
+		}
+	}
+}
+
+func TestValidCheckDigit(t *testing.T) {
+	tests := []struct {
+		scheme, id string
+		want       bool
+	}{
+		{"luhn", "79927398713", true},
+		{"luhn", "79927398710", false},
+		{"luhn", "79927398731", false}, // transposed
+		{"damm", "5724", true},
+		{"damm", "5742", false},
+		{"verhoeff", "2363", true},
+		{"verhoeff", "2 36 3", true},
+		{"verhoeff", "2633", false},
+	}
+	for _, tt := range tests {
+		got, err := ValidCheckDigit(tt.scheme, tt.id)
+		if err != nil || got != tt.want {
+			t.Errorf("ValidCheckDigit(%q, %q) = %v, %v, want %v", tt.scheme, tt.id, got, err, tt.want)
+		}
+	}
+	if _, err := ValidCheckDigit("luhn", "7"); err == nil {
+		t.Error("ValidCheckDigit of a single digit succeeded, want an error")
+	}
+}
@@ -0,0 +1,45 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+// CrockfordAlphabet is Douglas Crockford's Base32 alphabet, which leaves
+// out I, L, O and U so that codes survive being read out and retyped.
+const CrockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordCheckSymbols are the 37 check symbols: CrockfordAlphabet
+// followed by the five extra symbols for the values 32 to 36.
+const crockfordCheckSymbols = CrockfordAlphabet + "*~$=U"
+
+// Crockford32 renders h reduced to n symbols of Crockford's Base32, e.g.
+// "5TQ3K" for n = 5, followed by the check symbol of the rendered value
+// if check is set. It panics if n is outside [MinDigits, MaxDigits].
+func Crockford32(h uint64, n int, check bool) string {
+	code := formatDigits(h, n, CrockfordAlphabet)
+	if check {
+		m, _ := space(CrockfordAlphabet, n)
+		code += string(CrockfordCheck(h % m))
+	}
+	return code
+}
+
+// CrockfordCheck returns the Crockford Base32 check symbol of v, the
+// symbol for v mod 37, which catches single wrong and transposed symbols.
+func CrockfordCheck(v uint64) byte {
+	return crockfordCheckSymbols[v%37]
+}
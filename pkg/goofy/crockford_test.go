@@ -0,0 +1,61 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package goofy
+
+import "testing"
+
+func TestCrockford32(t *testing.T) {
+	tests := []struct {
+		h     uint64
+		n     int
+		check bool
+		want  string
+	}{
+		{0, 4, false, "0000"},
+		{0, 4, true, "00000"},
+		{1234, 4, false, "016J"},
+		{1234, 4, true, "016JD"},       // 1234 mod 37 = 13
+		{1<<20 + 33, 4, true, "0011~"}, // reduced to 33, a check-only symbol
+		{1<<20 - 1, 4, true, "ZZZZ*"},  // 1048575 mod 37 = 32
+		{1<<60 - 1, 12, false, "ZZZZZZZZZZZZ"},
+	}
+	for _, tt := range tests {
+		if got := Crockford32(tt.h, tt.n, tt.check); got != tt.want {
+			t.Errorf("Crockford32(%d, %d, %v) = %q, want %q", tt.h, tt.n, tt.check, got, tt.want)
+		}
+	}
+}
+
+func TestCrockfordCheck(t *testing.T) {
+	tests := []struct {
+		v    uint64
+		want byte
+	}{
+		{0, '0'},
+		{10, 'A'},
+		{31, 'Z'},
+		{32, '*'},
+		{36, 'U'},
+		{37, '0'},
+		{1234, 'D'},
+	}
+	for _, tt := range tests {
+		if got := CrockfordCheck(tt.v); got != tt.want {
+			t.Errorf("CrockfordCheck(%d) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
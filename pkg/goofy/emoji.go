@@ -0,0 +1,53 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxEmoji bounds the length of emoji codes, whose 6 bits per emoji
+// exhaust the 64-bit hash after 10.
+const MaxEmoji = 10
+
+// emojiSymbols are 64 emoji picked to be told apart at a glance, even
+// small: animals, food and objects of distinct shape and colour, each a
+// single code point that renders as emoji without a variation selector.
+var emojiSymbols = strings.Fields(`
+	🐶 🐱 🐭 🐰 🦊 🐻 🐼 🐨 🐯 🦁 🐮 🐷 🐸 🐵 🐔 🐧
+	🦒 🦆 🦉 🐴 🦄 🐝 🦔 🦋 🐌 🐞 🐢 🐍 🐙 🦀 🐳 🐬
+	🍎 🍌 🍇 🍓 🍒 🍑 🍍 🥕 🌽 🍄 🥐 🧀 🍕 🍩 🍪 🎂
+	🌵 🌻 🌙 🔥 🌈 🚀 🚲 🎈 🔑 🔔 🎸 🏀 💎 📚 🎩 🧲
+`)
+
+// Emoji renders h reduced to n emoji of a curated set of 64, so each one
+// carries six bits, e.g. "🦊🍕🚀🐢" for n = 4. It panics if n is outside
+// [MinDigits, MaxEmoji].
+func Emoji(h uint64, n int) string {
+	if n < MinDigits || n > MaxEmoji {
+		panic(fmt.Sprintf("goofy: emoji count out of range: %d", n))
+	}
+	symbols := make([]string, n)
+	for i := n - 1; i >= 0; i-- {
+		symbols[i] = emojiSymbols[h%64]
+		h /= 64
+	}
+	return strings.Join(symbols, "")
+}
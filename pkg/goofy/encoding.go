@@ -0,0 +1,77 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// DefaultBase is the radix IDs are rendered in when none is specified.
+const DefaultBase = 10
+
+// alphabets maps each supported base to the symbols it renders IDs with.
+var alphabets = map[int]string{
+	10: "0123456789",
+	36: "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	58: "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz", // Bitcoin alphabet
+	62: "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+}
+
+// Bases returns the supported ID bases, sorted.
+func Bases() []int {
+	bases := make([]int, 0, len(alphabets))
+	for b := range alphabets {
+		bases = append(bases, b)
+	}
+	sort.Ints(bases)
+	return bases
+}
+
+// space returns len(alphabet)**n, the number of distinct n-symbol codes,
+// and false if that does not fit in a uint64.
+func space(alphabet string, n int) (uint64, bool) {
+	p := uint64(1)
+	for i := 0; i < n; i++ {
+		hi, lo := bits.Mul64(p, uint64(len(alphabet)))
+		if hi != 0 {
+			return 0, false
+		}
+		p = lo
+	}
+	return p, true
+}
+
+// encode reduces h to n symbols of alphabet, most significant first and
+// padded with alphabet[0].
+func encode(h uint64, alphabet string, n int) string {
+	m, ok := space(alphabet, n)
+	if !ok {
+		panic(fmt.Sprintf("goofy: %d symbols of base %d overflow 64 bits", n, len(alphabet)))
+	}
+	h %= m
+	buf := make([]byte, n)
+	base := uint64(len(alphabet))
+	for i := n - 1; i >= 0; i-- {
+		buf[i] = alphabet[h%base]
+		h /= base
+	}
+	return string(buf)
+}
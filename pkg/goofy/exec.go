@@ -0,0 +1,285 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExecPrefix introduces an external hash algorithm: "exec:PATH" runs the
+// program at PATH as a helper computing the hashes.
+//
+// The helper is started once and kept running. For every hash goofy
+// writes a line with a JSON object to its stdin,
+//
+//	{"data":"aGVsbG8gd29ybGQh"}
+//
+// holding the base64-encoded message (see Message), and reads a line
+// with a JSON object from its stdout: the 64-bit hash in hexadecimal, or
+// an error.
+//
+//	{"hash":"40e99f25b19a5708"}
+//	{"error":"reason"}
+//
+// The helper's stderr is passed through. Requests are sent one at a
+// time, so a helper may be a simple read-eval-print loop. An error
+// response fails only the hash asked for; a helper that exits, or
+// answers with anything but such a line, is not asked again. CloseHelpers
+// closes the helpers' stdin, upon which they should exit.
+const ExecPrefix = "exec:"
+
+// execStopTimeout is how long a helper is given to exit once its stdin is
+// closed before it is killed.
+const execStopTimeout = 5 * time.Second
+
+// execRequest and execResponse are the messages of the helper protocol.
+type (
+	execRequest struct {
+		Data string `json:"data"` // base64
+	}
+	execResponse struct {
+		Hash  string `json:"hash"`
+		Error string `json:"error"`
+	}
+)
+
+// ExecError is the error of a failed exec: helper, and the value hashing
+// with it panics with; Catch and TryGenerate return it instead.
+type ExecError struct {
+	Path string
+	Err  error
+}
+
+func (e *ExecError) Error() string {
+	return "hash helper " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *ExecError) Unwrap() error { return e.Err }
+
+// refusedError is the error response of a helper to a single request.
+type refusedError string
+
+func (e refusedError) Error() string { return string(e) }
+
+// Catch calls fn and returns the *ExecError it panics with if an exec:
+// helper fails while fn hashes, instead of panicking. Other panics are
+// passed on.
+func Catch(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			execErr, ok := r.(*ExecError)
+			if !ok {
+				panic(r)
+			}
+			err = execErr
+		}
+	}()
+	fn()
+	return nil
+}
+
+// TryGenerate is like Generate, but returns the *ExecError of a failing
+// exec: helper instead of panicking.
+func TryGenerate(s string, opts Options) (id string, err error) {
+	err = Catch(func() { id = Generate(s, opts) })
+	return id, err
+}
+
+// TryVerify is like Verify, but returns the *ExecError of a failing exec:
+// helper instead of panicking.
+func TryVerify(s, id string, opts Options) (ok bool, err error) {
+	err = Catch(func() { ok = Verify(s, id, opts) })
+	return ok, err
+}
+
+// CloseHelpers closes the stdin of the exec: helpers started so far and
+// waits for them to exit, killing those that have not after a few
+// seconds. Hashers using them fail afterwards; NewHasher starts them
+// anew.
+func CloseHelpers() error {
+	execMu.Lock()
+	defer execMu.Unlock()
+	var errs []error
+	for path, h := range execHashers {
+		delete(execHashers, path)
+		h.mu.Lock()
+		if h.err == nil {
+			h.err = &ExecError{Path: h.path, Err: errors.New("closed")}
+			errs = append(errs, h.stop())
+		}
+		h.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// execHasher is a Hasher asking an external helper. Hashing panics with an
+// *ExecError if the helper fails; NewHasher hashes a probe message first
+// so that a helper that does not work at all is reported as an error
+// instead.
+type execHasher struct {
+	path string
+	mu   sync.Mutex
+	cmd  *exec.Cmd
+	in   io.WriteCloser
+	out  *bufio.Reader
+	// err is sticky: once the helper itself has failed, or was closed,
+	// it is not asked again.
+	err error
+}
+
+var (
+	execMu      sync.Mutex
+	execHashers = make(map[string]*execHasher) // by path
+)
+
+// newExecHasher returns the hasher running the helper at path, started
+// on first use and shared by all callers.
+func newExecHasher(path string) (*execHasher, error) {
+	if path == "" {
+		return nil, fmt.Errorf("hash algorithm %s requires a program, e.g. %s/usr/local/bin/myhash", ExecPrefix, ExecPrefix)
+	}
+	execMu.Lock()
+	defer execMu.Unlock()
+	if h, ok := execHashers[path]; ok {
+		// A helper that failed after starting fails the hashes asked
+		// of it, with an *ExecError, instead.
+		var execErr *ExecError
+		if errors.As(h.err, &execErr) {
+			return h, nil
+		}
+		return h, h.err
+	}
+	h := &execHasher{path: path}
+	execHashers[path] = h
+	if err := h.start(); err != nil {
+		h.err = err
+		return nil, err
+	}
+	if _, err := h.sum64(nil); err != nil {
+		// A helper refusing the probe would refuse everything.
+		if h.err == nil {
+			h.stop()
+		}
+		delete(execHashers, path)
+		return nil, err
+	}
+	return h, nil
+}
+
+// start starts the helper.
+func (h *execHasher) start() error {
+	cmd := exec.Command(h.path)
+	cmd.Stderr = os.Stderr
+	in, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting hash helper: %w", err)
+	}
+	h.cmd, h.in, h.out = cmd, in, bufio.NewReader(out)
+	return nil
+}
+
+// stop closes the helper's stdin and reaps it, killing it if it has not
+// exited after execStopTimeout.
+func (h *execHasher) stop() error {
+	h.in.Close()
+	timer := time.AfterFunc(execStopTimeout, func() { h.cmd.Process.Kill() })
+	defer timer.Stop()
+	return h.cmd.Wait()
+}
+
+// sum64 asks the helper for the hash of data. An error response fails
+// just this request; any other failure stops the helper for good.
+func (h *execHasher) sum64(data []byte) (uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return 0, h.err
+	}
+	sum, err := h.ask(data)
+	var refused refusedError
+	if errors.As(err, &refused) {
+		return 0, &ExecError{Path: h.path, Err: err}
+	}
+	if err != nil {
+		h.err = &ExecError{Path: h.path, Err: err}
+		h.cmd.Process.Kill() // it may not be listening anymore
+		h.stop()
+	}
+	return sum, h.err
+}
+
+// ask sends one request and reads the response.
+func (h *execHasher) ask(data []byte) (uint64, error) {
+	req, err := json.Marshal(execRequest{Data: base64.StdEncoding.EncodeToString(data)})
+	if err != nil {
+		return 0, err
+	}
+	if _, err := h.in.Write(append(req, '\n')); err != nil {
+		return 0, err
+	}
+	line, err := h.out.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		return 0, errors.New("exited without answering")
+	} else if err != nil {
+		return 0, err
+	}
+	var resp execResponse
+	if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		return 0, fmt.Errorf("malformed response %q: %w", strings.TrimSpace(line), err)
+	}
+	if resp.Error != "" {
+		return 0, refusedError(resp.Error)
+	}
+	if len(resp.Hash) != 16 {
+		return 0, fmt.Errorf("hash %q is not 16 hex digits", resp.Hash)
+	}
+	sum, err := strconv.ParseUint(resp.Hash, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("hash %q is not 16 hex digits", resp.Hash)
+	}
+	return sum, nil
+}
+
+// Sum64 returns the helper's hash of data. It panics with an *ExecError
+// if the helper fails.
+func (h *execHasher) Sum64(data []byte) uint64 {
+	sum, err := h.sum64(data)
+	if err != nil {
+		panic(err)
+	}
+	return sum
+}
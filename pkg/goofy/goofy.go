@@ -0,0 +1,508 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+// Package goofy generates short, human-friendly numeric IDs from strings.
+//
+// IDs are derived with a 64-bit hash (FNV-1a by default, see Algorithms)
+// over at most the first MaxBytes bytes (by default, see Options.MaxBytes)
+// of the UTF-8 encoded input, reduced
+// to a fixed number of decimal digits (6 by default) or, optionally,
+// symbols of a larger base (see Bases). Collisions are expected and
+// acceptable; the IDs are NOT suitable for security or as stable unique
+// identifiers.
+package goofy
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxBytes is the default maximum number of UTF-8 bytes processed
+	MaxBytes = 32
+	// NoTruncation as Options.MaxBytes hashes inputs of any length
+	NoTruncation = -1
+
+	// DefaultDigits is the ID length used when none is specified
+	DefaultDigits = 6
+	// DefaultGroup and DefaultSep give the "XX XX XX" spaced format
+	DefaultGroup = 2
+	DefaultSep   = " "
+	// MinDigits and MaxDigits bound the supported ID lengths
+	MinDigits = 4
+	MaxDigits = 12
+	// MaxConstruction is the latest message construction; see
+	// Options.Construction
+	MaxConstruction = 2
+)
+
+// Options controls how Generate renders an ID.
+type Options struct {
+	// Spaced formats the ID in groups of two digits, e.g. "XX XX XX"
+	// instead of "XXXXXX". Group and Sep change the grouping.
+	Spaced bool
+	// Group is the number of symbols per group when Spaced; zero means
+	// DefaultGroup.
+	Group int
+	// Sep separates the groups when Spaced; empty means DefaultSep.
+	Sep string
+	// Digits is the ID length in symbols of Base or Alphabet; zero means
+	// DefaultDigits.
+	Digits int
+	// Base is the radix the ID is rendered in (see Bases); zero means
+	// DefaultBase.
+	Base int
+	// Alphabet, if set, replaces Base with these symbols, e.g.
+	// "ABCDEFGHJKMNPQRSTUVWXYZ23456789": at least two distinct printable
+	// ASCII characters, none of them confusable with another (such as 0
+	// and O). DigitsFor picks a length for a given entropy.
+	Alphabet string
+	// NATO spells the ID out as NATO-style callouts, e.g. "two five niner
+	// one four four"; see FormatNATO. It takes precedence over Spaced.
+	NATO bool
+	// Words, if non-zero, renders the ID as that many words (MinWords to
+	// MaxWords) from a bundled wordlist followed by a two-digit number,
+	// e.g. "maple-otter-42", instead of digits.
+	Words int
+	// CheckDigit, if set, names a check digit scheme (see
+	// CheckDigitSchemes) whose digit is appended to the ID so that typos
+	// can be caught with ValidCheckDigit. It requires decimal IDs.
+	CheckDigit string
+	// Normalize, if set, names the Unicode normalization form (see
+	// Normalizations) inputs are brought into before hashing, so that
+	// composed and decomposed "é" get the same ID.
+	Normalize string
+	// Fold applies Unicode case folding before hashing, so that
+	// "Alice@Example.COM" and "alice@example.com" get the same ID.
+	Fold bool
+	// Trim strips leading and trailing white space before hashing.
+	Trim bool
+	// SquashSpaces collapses runs of white space into a single space
+	// before hashing, so inputs differing only in spacing get the same ID.
+	SquashSpaces bool
+	// MaxBytes is the number of UTF-8 bytes of the (preprocessed) input
+	// that are hashed; zero means the package default MaxBytes and
+	// NoTruncation hashes the whole input.
+	MaxBytes int
+	// Algo names the hash algorithm; empty means DefaultAlgo.
+	Algo string
+	// Key is the secret for keyed algorithms such as hmac-sha256.
+	Key []byte
+	// Salt, if set, is hashed ahead of the input so that deployments
+	// with different salts get independent ID spaces.
+	Salt string
+	// Namespace, if set, is hashed together with the input (much like
+	// UUIDv5 namespaces), so "orders" and "users" IDs are independent.
+	// Only with Construction 2 are they independent of salts as well.
+	Namespace string
+	// Construction selects how Message assembles the hashed bytes: 0 or
+	// 1 joins the parts with NUL bytes, as goofy always did, so a salt
+	// and a namespace of the same name give the same IDs; 2 tags and
+	// length-prefixes every part so that none can stand in for another.
+	// Construction 1 is the default to keep existing IDs stable.
+	Construction int
+	// Counter, if positive, is mixed into the hash to derive the
+	// Counter-th alternative ID for the same input; zero yields the
+	// regular ID.
+	Counter int
+	// Rotate, if set, names a rotation (see Rotations) or a window
+	// duration such as "30s" whose current period is mixed into the
+	// hash, so the same input yields a new ID every period.
+	Rotate string
+	// At is the time whose period Rotate selects; zero means now.
+	At time.Time
+	// Range, if set, confines IDs to the values it holds, which must lie
+	// within the full ID space; the hash is reduced into it without bias.
+	Range *Range
+}
+
+// Validate reports whether opts describes a supported configuration.
+func (opts Options) Validate() error {
+	if opts.Digits != 0 && (opts.Digits < MinDigits || opts.Digits > MaxDigits) {
+		return fmt.Errorf("digits must be between %d and %d, got %d", MinDigits, MaxDigits, opts.Digits)
+	}
+	if opts.Alphabet != "" {
+		if opts.Base != 0 {
+			return fmt.Errorf("a custom alphabet replaces the base, set only one")
+		}
+		if err := checkAlphabet(opts.Alphabet); err != nil {
+			return err
+		}
+	} else if _, ok := alphabets[opts.base()]; !ok {
+		return fmt.Errorf("unsupported base %d, want one of %v", opts.Base, Bases())
+	}
+	alphabet := opts.alphabet()
+	if _, ok := space(alphabet, opts.digits()); !ok {
+		return fmt.Errorf("%d digits of base %d exceed the 64-bit hash", opts.digits(), len(alphabet))
+	}
+	if opts.Group < 0 {
+		return fmt.Errorf("group size must not be negative, got %d", opts.Group)
+	}
+	if opts.Words != 0 && (opts.Words < MinWords || opts.Words > MaxWords) {
+		return fmt.Errorf("words must be between %d and %d, got %d", MinWords, MaxWords, opts.Words)
+	}
+	if opts.Words != 0 && opts.Alphabet != "" {
+		return fmt.Errorf("word-based IDs cannot use a custom alphabet")
+	}
+	if opts.Words != 0 && opts.NATO {
+		return fmt.Errorf("word-based IDs cannot be spelled out as NATO callouts")
+	}
+	if opts.CheckDigit != "" {
+		if _, ok := checkDigitSchemes[opts.CheckDigit]; !ok {
+			return fmt.Errorf("unknown check digit scheme %q, want one of %v", opts.CheckDigit, CheckDigitSchemes())
+		}
+		if opts.Words != 0 || opts.Alphabet != "" || opts.base() != DefaultBase {
+			return fmt.Errorf("check digits require decimal IDs")
+		}
+	}
+	if _, ok := normForms[opts.Normalize]; opts.Normalize != "" && !ok {
+		return fmt.Errorf("unknown normalization form %q, want one of %v", opts.Normalize, Normalizations())
+	}
+	if opts.MaxBytes < NoTruncation {
+		return fmt.Errorf("max bytes must not be negative, got %d", opts.MaxBytes)
+	}
+	if opts.Counter < 0 {
+		return fmt.Errorf("counter must not be negative, got %d", opts.Counter)
+	}
+	if opts.Construction < 0 || opts.Construction > MaxConstruction {
+		return fmt.Errorf("construction must be between 0 and %d, got %d", MaxConstruction, opts.Construction)
+	}
+	if r := opts.Range; r != nil {
+		if full := opts.fullSpace(); r.Lo > r.Hi || r.Hi >= full {
+			return fmt.Errorf("ID range %v must be ascending and within 0-%d", r, full-1)
+		}
+	}
+	if opts.Rotate != "" {
+		if _, err := lookupRotation(opts.Rotate); err != nil {
+			return err
+		}
+	}
+	if _, err := NewHasher(opts.Algo, opts.Key); err != nil {
+		return err
+	}
+	return nil
+}
+
+// digits returns the effective ID length.
+func (opts Options) digits() int {
+	if opts.Digits == 0 {
+		return DefaultDigits
+	}
+	return opts.Digits
+}
+
+// group returns the effective group size.
+func (opts Options) group() int {
+	if opts.Group == 0 {
+		return DefaultGroup
+	}
+	return opts.Group
+}
+
+// sep returns the effective group separator.
+func (opts Options) sep() string {
+	if opts.Sep == "" {
+		return DefaultSep
+	}
+	return opts.Sep
+}
+
+// maxBytes returns the effective truncation limit, or NoTruncation.
+func (opts Options) maxBytes() int {
+	if opts.MaxBytes == 0 {
+		return MaxBytes
+	}
+	return opts.MaxBytes
+}
+
+// base returns the effective ID base.
+func (opts Options) base() int {
+	if opts.Base == 0 {
+		return DefaultBase
+	}
+	return opts.Base
+}
+
+// Generate returns the ID for s, formatted according to opts.
+// It panics if opts is invalid; see Options.Validate.
+func Generate(s string, opts Options) string {
+	return GenerateFromSum64(Sum64(s, opts), opts)
+}
+
+// GenerateFromSum64 returns the ID for the hash h, as returned by Sum64,
+// formatted according to opts, so that Generate(s, opts) is
+// GenerateFromSum64(Sum64(s, opts), opts). It lets callers needing both
+// the hash and the ID of s hash it once. It panics if opts is invalid.
+func GenerateFromSum64(h uint64, opts Options) string {
+	if opts.Range != nil {
+		h = opts.Range.reduce(h)
+	}
+	if opts.Words != 0 {
+		return encodeWords(h, opts.Words)
+	}
+	id := formatDigits(h, opts.digits(), opts.alphabet())
+	if opts.CheckDigit != "" {
+		c, err := CheckDigit(opts.CheckDigit, id)
+		if err != nil {
+			panic("goofy: " + err.Error())
+		}
+		id += string(c)
+	}
+	if opts.NATO {
+		return FormatNATO(id)
+	}
+	if opts.Spaced {
+		return FormatGrouped(id, opts.group(), opts.sep())
+	}
+	return id
+}
+
+// Verify reports whether id is the ID of s under opts. Spaces and the
+// opts.Sep separator in id are ignored, so "259144" and "25 91 44" both
+// verify. The comparison runs in constant time so that keyed IDs don't
+// leak through timing.
+func Verify(s, id string, opts Options) bool {
+	got := strings.ReplaceAll(id, " ", "")
+	if opts.Sep != "" {
+		got = strings.ReplaceAll(got, opts.Sep, "")
+	}
+	opts.Spaced = false
+	opts.NATO = false
+	want := Generate(s, opts)
+	return subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1
+}
+
+// Sum64 returns the 64-bit hash an ID for s is derived from: the hash
+// selected by opts.Algo (keyed with opts.Key) over the message built by
+// Message. It panics if opts is invalid.
+func Sum64(s string, opts Options) uint64 {
+	h, err := NewHasher(opts.Algo, opts.Key)
+	if err != nil {
+		panic("goofy: " + err.Error())
+	}
+	return h.Sum64(Message(s, opts))
+}
+
+// Value returns the number the ID for s encodes: Sum64 reduced to the
+// opts.Digits symbols of opts.Base or opts.Alphabet, or to the words and
+// number of opts.Words, or into opts.Range if set. It excludes any check
+// digit and panics if opts is invalid.
+func Value(s string, opts Options) uint64 {
+	return ValueFromSum64(Sum64(s, opts), opts)
+}
+
+// ValueFromSum64 returns the number the ID for the hash h, as returned by
+// Sum64, encodes under opts; see Value.
+func ValueFromSum64(h uint64, opts Options) uint64 {
+	if opts.Range != nil {
+		return opts.Range.reduce(h)
+	}
+	return h % opts.Space()
+}
+
+// Space returns the number of distinct IDs under opts, not counting any
+// check digit: the size of opts.Range if set. It panics if opts is
+// invalid.
+func (opts Options) Space() uint64 {
+	if opts.Range != nil {
+		return opts.Range.Size()
+	}
+	return opts.fullSpace()
+}
+
+// fullSpace returns the number of distinct IDs under opts without
+// opts.Range.
+func (opts Options) fullSpace() uint64 {
+	if opts.Words != 0 {
+		m := uint64(100)
+		for i := 0; i < opts.Words; i++ {
+			m *= uint64(len(wordlist))
+		}
+		return m
+	}
+	m, _ := space(opts.alphabet(), opts.digits())
+	return m
+}
+
+// Digest returns the full digest of the message for s under opts, of
+// which Sum64 is a prefix, or nil if opts.Algo is not a Digester. It
+// panics if opts is invalid.
+func Digest(s string, opts Options) []byte {
+	h, err := NewHasher(opts.Algo, opts.Key)
+	if err != nil {
+		panic("goofy: " + err.Error())
+	}
+	d, ok := h.(Digester)
+	if !ok {
+		return nil
+	}
+	return d.Digest(Message(s, opts))
+}
+
+// Message returns the bytes that are hashed for s: the part of s returned
+// by HashedInput, preceded by "salt\x00" if opts.Salt is set, by
+// "period\x00" if opts.Rotate is set and by "namespace\x00" if
+// opts.Namespace is set, and followed by "\x00counter" (in decimal) if
+// opts.Counter is positive. None of these count against opts.MaxBytes.
+// With opts.Construction 2 the message is "goofy2" followed by a tag
+// byte, the uvarint length and the bytes of each part instead: 's' for
+// the salt, 'p' the period, 'n' the namespace, 'c' the counter (the
+// first three only if set, the counter only if positive) and finally 'i'
+// for the input.
+func Message(s string, opts Options) []byte {
+	truncated, _ := HashedInput(s, opts)
+	period := opts.period()
+	if opts.Construction == 2 {
+		return taggedMessage(truncated, period, opts)
+	}
+
+	if opts.Salt == "" && period == "" && opts.Namespace == "" && opts.Counter <= 0 {
+		return []byte(truncated)
+	}
+	msg := make([]byte, 0, len(opts.Salt)+len(period)+len(opts.Namespace)+len(truncated)+24)
+	if opts.Salt != "" {
+		msg = append(msg, opts.Salt...)
+		msg = append(msg, 0)
+	}
+	if period != "" {
+		msg = append(msg, period...)
+		msg = append(msg, 0)
+	}
+	if opts.Namespace != "" {
+		msg = append(msg, opts.Namespace...)
+		msg = append(msg, 0)
+	}
+	msg = append(msg, truncated...)
+	if opts.Counter > 0 {
+		msg = append(msg, 0)
+		msg = strconv.AppendInt(msg, int64(opts.Counter), 10)
+	}
+	return msg
+}
+
+// taggedMessage returns the message of construction 2 for the hashed
+// input and period; see Message.
+func taggedMessage(input, period string, opts Options) []byte {
+	msg := make([]byte, 0, 6+len(opts.Salt)+len(period)+len(opts.Namespace)+len(input)+40)
+	msg = append(msg, "goofy2"...)
+	part := func(tag byte, value string) {
+		msg = append(msg, tag)
+		msg = binary.AppendUvarint(msg, uint64(len(value)))
+		msg = append(msg, value...)
+	}
+	if opts.Salt != "" {
+		part('s', opts.Salt)
+	}
+	if period != "" {
+		part('p', period)
+	}
+	if opts.Namespace != "" {
+		part('n', opts.Namespace)
+	}
+	if opts.Counter > 0 {
+		part('c', strconv.Itoa(opts.Counter))
+	}
+	part('i', input)
+	return msg
+}
+
+// HashedInput returns the part of s that is hashed under opts: s after
+// Preprocess, truncated to opts.MaxBytes bytes without splitting UTF-8
+// sequences. truncated reports whether anything was cut off.
+func HashedInput(s string, opts Options) (hashed string, truncated bool) {
+	s = Preprocess(s, opts)
+	if opts.maxBytes() == NoTruncation {
+		return s, false
+	}
+	hashed = TruncateUTF8(s, opts.maxBytes())
+	return hashed, len(hashed) < len(s)
+}
+
+// SixDigitID generates a 6-digit ID from a string using FNV-1a hash.
+// It processes up to the first MaxBytes (32) bytes of UTF-8 encoding,
+// ensuring multibyte sequences are not split.
+//
+// Returns a 6-digit string (000000-999999).
+// Collisions are expected and acceptable.
+func SixDigitID(s string) string {
+	return NDigitID(s, DefaultDigits)
+}
+
+// NDigitID generates an n-digit ID from a string using FNV-1a hash,
+// processing the input exactly like SixDigitID.
+//
+// Returns a zero-padded string of n digits. It panics if n is outside
+// [MinDigits, MaxDigits].
+func NDigitID(s string, n int) string {
+	return formatDigits(Sum64(s, Options{}), n, alphabets[DefaultBase])
+}
+
+// formatDigits reduces h to n symbols of alphabet, padded with its first.
+func formatDigits(h uint64, n int, alphabet string) string {
+	if n < MinDigits || n > MaxDigits {
+		panic(fmt.Sprintf("goofy: digits out of range: %d", n))
+	}
+	return encode(h, alphabet, n)
+}
+
+// TruncateUTF8 truncates s to at most maxBytes bytes,
+// ensuring we don't split a multibyte UTF-8 sequence.
+func TruncateUTF8(s string, maxBytes int) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+
+	// Find the last valid rune boundary at or before maxBytes
+	for i := maxBytes; i > 0; i-- {
+		if utf8.RuneStart(s[i]) {
+			return s[:i]
+		}
+	}
+
+	// If we can't find a valid boundary, return empty
+	return ""
+}
+
+// FormatSpaced formats an ID in space-separated groups of two symbols,
+// e.g. "XX XX XX" for 6 digits or "Ab 3x" for a 4-symbol base-62 code.
+// For odd lengths the last group holds a single symbol.
+func FormatSpaced(id string) string {
+	return FormatGrouped(id, DefaultGroup, DefaultSep)
+}
+
+// FormatGrouped formats an ID in groups of size symbols joined by sep,
+// e.g. "259-144" for size 3 and sep "-". If the length is not a multiple
+// of size the last group is shorter. It panics if size is not positive.
+func FormatGrouped(id string, size int, sep string) string {
+	if size <= 0 {
+		panic(fmt.Sprintf("goofy: group size must be positive: %d", size))
+	}
+	var b strings.Builder
+	for i := 0; i < len(id); i += size {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(id[i:min(i+size, len(id))])
+	}
+	return b.String()
+}
@@ -0,0 +1,373 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"hash/crc64"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/zeebo/blake3"
+)
+
+// DefaultAlgo is the hash algorithm used when none is specified.
+// Changing it would change every previously generated ID.
+const DefaultAlgo = "fnv1a"
+
+// Hasher computes the 64-bit hash an ID is derived from.
+type Hasher interface {
+	Sum64(data []byte) uint64
+}
+
+// Digester is implemented by Hashers whose full digest is longer than the
+// 64 bits IDs are derived from.
+type Digester interface {
+	Hasher
+	// Digest returns the full digest of data.
+	Digest(data []byte) []byte
+}
+
+// HasherFunc adapts an ordinary function to the Hasher interface.
+type HasherFunc func(data []byte) uint64
+
+// Sum64 returns f(data).
+func (f HasherFunc) Sum64(data []byte) uint64 {
+	return f(data)
+}
+
+// hashers maps algorithm names to their implementations.
+var hashers = map[string]Hasher{
+	"fnv1a":    HasherFunc(fnv1a),
+	"fnv1":     HasherFunc(fnv1),
+	"xxhash64": HasherFunc(xxhash64),
+	"sha256":   sha256Hasher{},
+	"blake3":   blake3Hasher{},
+	"crc32":    HasherFunc(crc32Sum64),
+	"crc64":    HasherFunc(crc64Sum64),
+}
+
+// algoAliases maps alternative algorithm names to the ones in hashers.
+// They are accepted wherever an algorithm is named but not listed by
+// Algorithms.
+var algoAliases = map[string]string{
+	"xxhash": "xxhash64",
+}
+
+// keyedHasher describes an algorithm taking a key.
+type keyedHasher struct {
+	new      func(key []byte) Hasher
+	size     int  // required key length in bytes, 0 for any
+	optional bool // the key may be omitted
+}
+
+// keyedHashers maps keyed algorithm names to their implementations.
+var keyedHashers = map[string]keyedHasher{
+	"hmac-sha256": {new: newHMACSHA256},
+	// Without a key siphash uses the all-zero key, as it did before it
+	// accepted one, so existing IDs stay valid.
+	"siphash": {new: newSipHash, size: 16, optional: true},
+}
+
+// Algorithms returns the names of the supported hash algorithms, sorted.
+func Algorithms() []string {
+	names := make([]string, 0, len(hashers)+len(keyedHashers))
+	for name := range hashers {
+		names = append(names, name)
+	}
+	for name := range keyedHashers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Keyed reports whether the named algorithm takes a key.
+func Keyed(name string) bool {
+	_, ok := keyedHashers[name]
+	return ok
+}
+
+// KeySize returns the key length in bytes the named algorithm requires,
+// or 0 if it takes keys of any length or none at all.
+func KeySize(name string) int {
+	return keyedHashers[name].size
+}
+
+// LookupHasher returns the Hasher for the named unkeyed algorithm.
+// The empty name selects DefaultAlgo.
+func LookupHasher(name string) (Hasher, error) {
+	return NewHasher(name, nil)
+}
+
+// NewHasher returns the Hasher for the named algorithm. Keyed algorithms
+// require a key (hmac-sha256 of any length) or accept one (siphash, of
+// 16 bytes); all others reject one. The empty name selects DefaultAlgo,
+// and a name starting with ExecPrefix an external helper, whose failures
+// hashing later make Sum64, Generate and the like panic with an
+// *ExecError; see Catch and TryGenerate.
+func NewHasher(name string, key []byte) (Hasher, error) {
+	if name == "" {
+		name = DefaultAlgo
+	}
+	if path, ok := strings.CutPrefix(name, ExecPrefix); ok {
+		if len(key) != 0 {
+			return nil, fmt.Errorf("hash algorithm %s does not take a key", ExecPrefix)
+		}
+		return newExecHasher(path)
+	}
+	if alias, ok := algoAliases[name]; ok {
+		name = alias
+	}
+	if k, ok := keyedHashers[name]; ok {
+		switch {
+		case len(key) == 0 && !k.optional:
+			return nil, fmt.Errorf("hash algorithm %s requires a key", name)
+		case len(key) != 0 && k.size != 0 && len(key) != k.size:
+			return nil, fmt.Errorf("hash algorithm %s requires a %d-bit key, got %d bytes", name, 8*k.size, len(key))
+		}
+		return k.new(key), nil
+	}
+	h, ok := hashers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash algorithm %q", name)
+	}
+	if len(key) != 0 {
+		return nil, fmt.Errorf("hash algorithm %s does not take a key", name)
+	}
+	return h, nil
+}
+
+// FNV-1 and FNV-1a 64-bit constants
+const (
+	offset64 = 1469598103934665603
+	prime64  = 1099511628211
+)
+
+// fnv1a returns the FNV-1a 64-bit hash of data.
+func fnv1a(data []byte) uint64 {
+	var h uint64 = offset64
+	for _, c := range data {
+		h ^= uint64(c)
+		h *= prime64
+	}
+	return h
+}
+
+// fnv1 returns the FNV-1 64-bit hash of data.
+func fnv1(data []byte) uint64 {
+	var h uint64 = offset64
+	for _, c := range data {
+		h *= prime64
+		h ^= uint64(c)
+	}
+	return h
+}
+
+// sha256Hasher computes the first 8 bytes of the SHA-256 digest of data,
+// read as a big-endian integer.
+type sha256Hasher struct{}
+
+func (sha256Hasher) Sum64(data []byte) uint64 {
+	sum := sha256.Sum256(data)
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+func (sha256Hasher) Digest(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// crc32Sum64 returns the CRC-32 (IEEE, as used by zlib and Ethernet) of
+// data. Being 32 bits it reduces to at most 4294967296 distinct IDs, but
+// it reproduces codes of legacy systems computing crc32(input) mod 10^n.
+func crc32Sum64(data []byte) uint64 {
+	return uint64(crc32.ChecksumIEEE(data))
+}
+
+// crc64Table is the CRC-64 polynomial of ECMA-182 (CRC-64/XZ).
+var crc64Table = crc64.MakeTable(crc64.ECMA)
+
+// crc64Sum64 returns the CRC-64/XZ of data.
+func crc64Sum64(data []byte) uint64 {
+	return crc64.Checksum(data, crc64Table)
+}
+
+// blake3Hasher computes the first 8 bytes of the 256-bit BLAKE3 digest,
+// read as a big-endian integer.
+type blake3Hasher struct{}
+
+func (blake3Hasher) Sum64(data []byte) uint64 {
+	sum := blake3.Sum256(data)
+	return binary.BigEndian.Uint64(sum[:8])
+}
+
+func (blake3Hasher) Digest(data []byte) []byte {
+	sum := blake3.Sum256(data)
+	return sum[:]
+}
+
+// newHMACSHA256 returns a Hasher computing the first 8 bytes of
+// HMAC-SHA256(key, data), read as a big-endian integer.
+func newHMACSHA256(key []byte) Hasher {
+	return HasherFunc(func(data []byte) uint64 {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(data)
+		return binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+	})
+}
+
+// newSipHash returns SipHash-2-4 keyed with the 16-byte key, or with the
+// all-zero key if key is empty.
+func newSipHash(key []byte) Hasher {
+	var k0, k1 uint64
+	if len(key) != 0 {
+		k0 = binary.LittleEndian.Uint64(key[:8])
+		k1 = binary.LittleEndian.Uint64(key[8:16])
+	}
+	return HasherFunc(func(data []byte) uint64 {
+		return siphash24(k0, k1, data)
+	})
+}
+
+// xxHash64 constants
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxhash64 returns the xxHash64 (seed 0) of data.
+func xxhash64(data []byte) uint64 {
+	var seed, h uint64
+	n := len(data)
+
+	if n >= 32 {
+		v1 := seed + xxPrime1 + xxPrime2
+		v2 := seed + xxPrime2
+		v3 := seed
+		v4 := seed - xxPrime1
+		for len(data) >= 32 {
+			v1 = xxRound(v1, binary.LittleEndian.Uint64(data[0:8]))
+			v2 = xxRound(v2, binary.LittleEndian.Uint64(data[8:16]))
+			v3 = xxRound(v3, binary.LittleEndian.Uint64(data[16:24]))
+			v4 = xxRound(v4, binary.LittleEndian.Uint64(data[24:32]))
+			data = data[32:]
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) +
+			bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	} else {
+		h = seed + xxPrime5
+	}
+
+	h += uint64(n)
+
+	for ; len(data) >= 8; data = data[8:] {
+		h ^= xxRound(0, binary.LittleEndian.Uint64(data))
+		h = bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+	}
+	if len(data) >= 4 {
+		h ^= uint64(binary.LittleEndian.Uint32(data)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		data = data[4:]
+	}
+	for _, c := range data {
+		h ^= uint64(c) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+
+	h ^= h >> 33
+	h *= xxPrime2
+	h ^= h >> 29
+	h *= xxPrime3
+	h ^= h >> 32
+	return h
+}
+
+func xxRound(acc, input uint64) uint64 {
+	acc += input * xxPrime2
+	acc = bits.RotateLeft64(acc, 31)
+	return acc * xxPrime1
+}
+
+func xxMergeRound(acc, val uint64) uint64 {
+	acc ^= xxRound(0, val)
+	return acc*xxPrime1 + xxPrime4
+}
+
+// siphash24 returns the SipHash-2-4 of data under the 128-bit key (k0, k1).
+func siphash24(k0, k1 uint64, data []byte) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575
+	v1 := k1 ^ 0x646f72616e646f6d
+	v2 := k0 ^ 0x6c7967656e657261
+	v3 := k1 ^ 0x7465646279746573
+
+	round := func() {
+		v0 += v1
+		v1 = bits.RotateLeft64(v1, 13)
+		v1 ^= v0
+		v0 = bits.RotateLeft64(v0, 32)
+		v2 += v3
+		v3 = bits.RotateLeft64(v3, 16)
+		v3 ^= v2
+		v0 += v3
+		v3 = bits.RotateLeft64(v3, 21)
+		v3 ^= v0
+		v2 += v1
+		v1 = bits.RotateLeft64(v1, 17)
+		v1 ^= v2
+		v2 = bits.RotateLeft64(v2, 32)
+	}
+
+	n := len(data)
+	for ; len(data) >= 8; data = data[8:] {
+		m := binary.LittleEndian.Uint64(data)
+		v3 ^= m
+		round()
+		round()
+		v0 ^= m
+	}
+
+	last := uint64(n) << 56
+	for i, c := range data {
+		last |= uint64(c) << (8 * i)
+	}
+	v3 ^= last
+	round()
+	round()
+	v0 ^= last
+
+	v2 ^= 0xff
+	round()
+	round()
+	round()
+	round()
+	return v0 ^ v1 ^ v2 ^ v3
+}
@@ -0,0 +1,56 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import "strings"
+
+// natoDigits are the ICAO/NATO radiotelephony words for 0-9.
+var natoDigits = [10]string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "niner",
+}
+
+// natoLetters are the ICAO/NATO spelling alphabet words for A-Z.
+var natoLetters = [26]string{
+	"alfa", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel",
+	"india", "juliett", "kilo", "lima", "mike", "november", "oscar", "papa",
+	"quebec", "romeo", "sierra", "tango", "uniform", "victor", "whiskey",
+	"xray", "yankee", "zulu",
+}
+
+// FormatNATO spells out an ID as NATO-style callouts, e.g. "259144"
+// becomes "two five niner one four four". Letters use the spelling
+// alphabet, with lowercase ones prefixed by "lower"; spaces are skipped
+// and any other character is passed through as is.
+func FormatNATO(id string) string {
+	words := make([]string, 0, len(id))
+	for _, c := range id {
+		switch {
+		case c >= '0' && c <= '9':
+			words = append(words, natoDigits[c-'0'])
+		case c >= 'A' && c <= 'Z':
+			words = append(words, natoLetters[c-'A'])
+		case c >= 'a' && c <= 'z':
+			words = append(words, "lower "+natoLetters[c-'a'])
+		case c == ' ':
+		default:
+			words = append(words, string(c))
+		}
+	}
+	return strings.Join(words, " ")
+}
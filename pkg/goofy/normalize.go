@@ -0,0 +1,87 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// normForms maps Unicode normalization form names to their forms.
+var normForms = map[string]norm.Form{
+	"nfc":  norm.NFC,
+	"nfd":  norm.NFD,
+	"nfkc": norm.NFKC,
+	"nfkd": norm.NFKD,
+}
+
+// Normalizations returns the names of the supported Unicode normalization
+// forms, sorted.
+func Normalizations() []string {
+	names := make([]string, 0, len(normForms))
+	for name := range normForms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Preprocess returns s as it is hashed before truncation to MaxBytes:
+// normalized to opts.Normalize if set, then trimmed and squashed as
+// selected by opts.Trim and opts.SquashSpaces, then case folded if
+// opts.Fold.
+func Preprocess(s string, opts Options) string {
+	if form, ok := normForms[opts.Normalize]; ok {
+		s = form.String(s)
+	}
+	if opts.Trim {
+		s = strings.TrimSpace(s)
+	}
+	if opts.SquashSpaces {
+		s = squashSpaces(s)
+	}
+	if opts.Fold {
+		s = cases.Fold().String(s)
+	}
+	return s
+}
+
+// squashSpaces replaces every run of Unicode white space in s with a
+// single ASCII space.
+func squashSpaces(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	inSpace := false
+	for _, r := range s {
+		if unicode.IsSpace(r) {
+			if !inSpace {
+				b.WriteByte(' ')
+			}
+			inSpace = true
+			continue
+		}
+		inSpace = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prefixTries is how many times the expected number of counters
+// PrefixCounter tries before giving up; failing to find a match within
+// that many has a probability of about e^-prefixTries.
+const prefixTries = 64
+
+// PrefixCounter returns the smallest Counter under which the ID of s
+// starts with prefix, so that IDs can be confined to a partition of the ID
+// space, e.g. one per department. The counter is the nonce needed to
+// verify the ID later: Verify(s, id, opts) with opts.Counter set to it.
+// The search is deterministic and takes about base^len(prefix) hashes. It
+// fails if prefix cannot start an ID under opts, and panics if opts is
+// invalid.
+func PrefixCounter(s, prefix string, opts Options) (int, error) {
+	if opts.Words != 0 {
+		return 0, fmt.Errorf("ID prefixes require digit-based IDs, not words")
+	}
+	alphabet := opts.alphabet()
+	if prefix == "" || len(prefix) >= opts.digits() {
+		return 0, fmt.Errorf("prefix %q must be 1 to %d symbols long", prefix, opts.digits()-1)
+	}
+	for _, r := range prefix {
+		if !strings.ContainsRune(alphabet, r) {
+			return 0, fmt.Errorf("prefix %q contains %q, which is not an ID symbol (%s)", prefix, r, alphabet)
+		}
+	}
+	limit := 1 << 31
+	if expected, ok := space(alphabet, len(prefix)); ok && expected < uint64(limit)/prefixTries {
+		limit = int(expected) * prefixTries
+	}
+
+	opts.Spaced = false
+	opts.NATO = false
+	for n := 0; n < limit; n++ {
+		opts.Counter = n
+		if strings.HasPrefix(Generate(s, opts), prefix) {
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("no ID of %q starts with %q within %d counters", s, prefix, limit)
+}
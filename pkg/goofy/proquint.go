@@ -0,0 +1,51 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import "strings"
+
+// Proquint consonants and vowels: each quintet consonant-vowel-consonant-
+// vowel-consonant spells 4+2+4+2+4 = 16 bits.
+const (
+	proquintConsonants = "bdfghjklmnprstvz"
+	proquintVowels     = "aiou"
+)
+
+// Proquint renders the low 16n bits of h as n pronounceable quintets
+// joined with hyphens, most significant first (see
+// https://arxiv.org/abs/0901.4016): 0x7f000001 with two quintets is
+// "lusab-babad". It panics unless n is 1 to 4.
+func Proquint(h uint64, n int) string {
+	if n < 1 || n > 4 {
+		panic("goofy: proquint quintets out of range")
+	}
+	quints := make([]string, n)
+	for i := n - 1; i >= 0; i-- {
+		q := uint16(h)
+		quints[i] = string([]byte{
+			proquintConsonants[q>>12],
+			proquintVowels[q>>10&3],
+			proquintConsonants[q>>6&15],
+			proquintVowels[q>>4&3],
+			proquintConsonants[q&15],
+		})
+		h >>= 16
+	}
+	return strings.Join(quints, "-")
+}
@@ -0,0 +1,54 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package goofy
+
+import "testing"
+
+func TestProquint(t *testing.T) {
+	// The IPv4 examples of the proquint paper.
+	tests := []struct {
+		h    uint64
+		n    int
+		want string
+	}{
+		{0x7f000001, 2, "lusab-babad"}, // 127.0.0.1
+		{0x3f54dcc1, 2, "gutih-tugad"}, // 63.84.220.193
+		{0x8c62c18d, 2, "mudof-sakat"}, // 140.98.193.141
+		{0xffffffff, 2, "zuzuz-zuzuz"}, // 255.255.255.255
+		{0x7f000001, 1, "babad"},       // low 16 bits only
+		{0x1_7f000001, 3, "babad-lusab-babad"},
+		{0, 4, "babab-babab-babab-babab"},
+	}
+	for _, tt := range tests {
+		if got := Proquint(tt.h, tt.n); got != tt.want {
+			t.Errorf("Proquint(%#x, %d) = %q, want %q", tt.h, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestProquintPanics(t *testing.T) {
+	for _, n := range []int{0, 5} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Proquint(0, %d) did not panic", n)
+				}
+			}()
+			Proquint(0, n)
+		}()
+	}
+}
@@ -0,0 +1,73 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"math"
+)
+
+// Range is an inclusive range of ID values (see Value) that IDs are
+// confined to, so that e.g. each namespace can own a slice of the ID
+// space and an ID's provenance shows in its value.
+type Range struct {
+	Lo, Hi uint64
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d-%d", r.Lo, r.Hi)
+}
+
+// Size returns the number of values in r.
+func (r Range) Size() uint64 {
+	return r.Hi - r.Lo + 1
+}
+
+// Contains reports whether r holds the value v.
+func (r Range) Contains(v uint64) bool {
+	return r.Lo <= v && v <= r.Hi
+}
+
+// Overlaps reports whether r and o share a value.
+func (r Range) Overlaps(o Range) bool {
+	return r.Lo <= o.Hi && o.Lo <= r.Hi
+}
+
+// reduce maps the hash h uniformly onto r. Plain h % size favours the
+// low values whenever size does not divide 2^64, so hashes in that
+// incomplete last stretch of 2^64 are remixed until they fall below it;
+// a remix is needed with probability below size/2^64.
+func (r Range) reduce(h uint64) uint64 {
+	size := r.Size()
+	if tail := (math.MaxUint64%size + 1) % size; tail != 0 {
+		for h >= -tail {
+			h = mix64(h)
+		}
+	}
+	return r.Lo + h%size
+}
+
+// mix64 is the SplitMix64 finalizer, a bijection that scrambles the bits
+// of h.
+func mix64(h uint64) uint64 {
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ h>>30) * 0xbf58476d1ce4e5b9
+	h = (h ^ h>>27) * 0x94d049bb133111eb
+	return h ^ h>>31
+}
@@ -0,0 +1,74 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package goofy
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRangeReduce(t *testing.T) {
+	tests := []struct {
+		r    Range
+		h    uint64
+		want uint64
+	}{
+		{Range{0, 9}, 0, 0},
+		{Range{0, 9}, 123, 3},
+		{Range{10, 19}, 25, 15},
+		{Range{7, 7}, math.MaxUint64, 7},
+		{Range{0, 1<<32 - 1}, 1<<32 + 5, 5}, // size divides 2^64: no remix
+		// 2^64 - 1 lies in the incomplete last stretch of sizes 3 and 10,
+		// so it is remixed instead of taken mod size.
+		{Range{0, 2}, math.MaxUint64, mix64(math.MaxUint64) % 3},
+		{Range{100, 109}, math.MaxUint64, 100 + mix64(math.MaxUint64)%10},
+		{Range{0, math.MaxUint64 - 1}, math.MaxUint64, mix64(math.MaxUint64) % math.MaxUint64},
+	}
+	for _, tt := range tests {
+		got := tt.r.reduce(tt.h)
+		if got != tt.want {
+			t.Errorf("%v.reduce(%d) = %d, want %d", tt.r, tt.h, got, tt.want)
+		}
+		if !tt.r.Contains(got) {
+			t.Errorf("%v.reduce(%d) = %d, outside the range", tt.r, tt.h, got)
+		}
+	}
+}
+
+func TestRangeOverlaps(t *testing.T) {
+	tests := []struct {
+		r, o Range
+		want bool
+	}{
+		{Range{0, 9}, Range{10, 19}, false},
+		{Range{0, 9}, Range{9, 19}, true},
+		{Range{0, 9}, Range{3, 4}, true},
+		{Range{3, 4}, Range{0, 9}, true},
+		{Range{5, 5}, Range{5, 5}, true},
+		{Range{5, 5}, Range{6, 6}, false},
+		{Range{20, 29}, Range{0, 19}, false},
+		{Range{0, math.MaxUint64}, Range{math.MaxUint64, math.MaxUint64}, true},
+	}
+	for _, tt := range tests {
+		if got := tt.r.Overlaps(tt.o); got != tt.want {
+			t.Errorf("%v.Overlaps(%v) = %v, want %v", tt.r, tt.o, got, tt.want)
+		}
+		if got := tt.o.Overlaps(tt.r); got != tt.want {
+			t.Errorf("%v.Overlaps(%v) = %v, want %v", tt.o, tt.r, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// rotation names the period a time falls into and steps between periods.
+type rotation struct {
+	period func(t time.Time) string
+	shift  func(t time.Time, n int) time.Time // t moved by n periods
+}
+
+// rotations maps rotation names to their periods. Periods are computed
+// in UTC so that every deployment agrees on when they change.
+var rotations = map[string]rotation{
+	"daily": {
+		period: func(t time.Time) string { return t.Format("2006-01-02") },
+		shift:  func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) },
+	},
+	"weekly": {
+		period: func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%04d-W%02d", year, week)
+		},
+		shift: func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) },
+	},
+	"monthly": {
+		period: func(t time.Time) string { return t.Format("2006-01") },
+		shift: func(t time.Time, n int) time.Time {
+			// From the first of the month, so that shifting January 31st
+			// by one month does not overflow into March.
+			return time.Date(t.Year(), t.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+		},
+	},
+}
+
+// windowRotation returns the rotation into fixed windows of d, numbered
+// like TOTP time steps: the window containing t is t's Unix time divided
+// by d.
+func windowRotation(d time.Duration) rotation {
+	secs := int64(d / time.Second)
+	return rotation{
+		period: func(t time.Time) string {
+			n := t.Unix() / secs
+			if t.Unix()%secs < 0 {
+				n--
+			}
+			return strconv.FormatInt(n, 10)
+		},
+		shift: func(t time.Time, n int) time.Time { return t.Add(time.Duration(n) * d) },
+	}
+}
+
+// lookupRotation returns the rotation named name: one of Rotations or a
+// window duration of whole seconds such as "30s".
+func lookupRotation(name string) (rotation, error) {
+	if r, ok := rotations[name]; ok {
+		return r, nil
+	}
+	d, err := time.ParseDuration(name)
+	if err != nil {
+		return rotation{}, fmt.Errorf("unknown rotation %q, want one of %v or a duration", name, Rotations())
+	}
+	if d < time.Second || d%time.Second != 0 {
+		return rotation{}, fmt.Errorf("rotation window must be a positive number of seconds, got %v", d)
+	}
+	return windowRotation(d), nil
+}
+
+// Rotations returns the names of the supported calendar rotations,
+// sorted. Durations such as "30s" are accepted as rotations as well.
+func Rotations() []string {
+	names := make([]string, 0, len(rotations))
+	for name := range rotations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Period returns the name of the period of the named rotation that t
+// falls into, e.g. "2025-06-30" (daily), "2025-W27" (weekly), "2025-06"
+// (monthly) or "58373020" (the 58373020th 30s window since the Unix
+// epoch).
+func Period(rotation string, t time.Time) (string, error) {
+	r, err := lookupRotation(rotation)
+	if err != nil {
+		return "", err
+	}
+	return r.period(t.UTC()), nil
+}
+
+// period returns the period mixed into hashes under opts, or "" if IDs
+// do not rotate.
+func (opts Options) period() string {
+	if opts.Rotate == "" {
+		return ""
+	}
+	p, err := Period(opts.Rotate, opts.at())
+	if err != nil {
+		panic("goofy: " + err.Error())
+	}
+	return p
+}
+
+// at returns the time whose period opts.Rotate selects.
+func (opts Options) at() time.Time {
+	if opts.At.IsZero() {
+		return time.Now()
+	}
+	return opts.At
+}
+
+// VerifySkew is like Verify for rotating IDs but also accepts the IDs of
+// up to skew periods before and after the one containing opts.At, to
+// allow for clock drift and for codes entered just as a period ends.
+func VerifySkew(s, id string, opts Options, skew int) bool {
+	if opts.Rotate == "" {
+		return Verify(s, id, opts)
+	}
+	r, err := lookupRotation(opts.Rotate)
+	if err != nil {
+		return false
+	}
+	at := opts.at().UTC()
+	ok := false
+	for n := -skew; n <= skew; n++ {
+		opts.At = r.shift(at, n)
+		// No early return, so that the time taken does not reveal
+		// which period matched.
+		if Verify(s, id, opts) {
+			ok = true
+		}
+	}
+	return ok
+}
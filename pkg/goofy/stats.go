@@ -0,0 +1,69 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import "math"
+
+// ExpectedCollisions returns the expected number of collisions when n
+// distinct inputs are hashed uniformly into space IDs: the number of
+// inputs that land on an ID taken by an earlier one.
+func ExpectedCollisions(n, space uint64) float64 {
+	if n == 0 || space == 0 {
+		return 0
+	}
+	m := float64(space)
+	// m(1-1/m)^n is the expected number of unused IDs.
+	free := m * math.Exp(float64(n)*math.Log1p(-1/m))
+	return float64(n) - (m - free)
+}
+
+// CollisionProbability returns the probability that at least two of n
+// distinct inputs hashed uniformly into space IDs share an ID (the
+// birthday problem), using the approximation 1 - e^(-n(n-1)/2m).
+func CollisionProbability(n, space uint64) float64 {
+	if n < 2 {
+		return 0
+	}
+	if n > space {
+		return 1
+	}
+	pairs := float64(n) * float64(n-1) / 2
+	return -math.Expm1(pairs * math.Log1p(-1/float64(space)))
+}
+
+// SafeInputs returns the largest number of distinct inputs that can be
+// hashed into space IDs while the probability of a collision stays at
+// or below p.
+func SafeInputs(space uint64, p float64) uint64 {
+	if p <= 0 {
+		return 1
+	}
+	if p >= 1 {
+		return math.MaxUint64
+	}
+	// Invert the approximation, then correct for rounding.
+	n := uint64(math.Sqrt(2 * float64(space) * -math.Log1p(-p)))
+	for n > 1 && CollisionProbability(n, space) > p {
+		n--
+	}
+	for CollisionProbability(n+1, space) <= p {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,76 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+package goofy
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseToken(t *testing.T) {
+	key := []byte("secret")
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(15 * time.Minute)
+	opts := Options{Digits: 6}
+	token := Token("hello", expires, key, opts)
+	parts := strings.Split(token, ".")
+	id, exp, tag := parts[0], parts[1], parts[2]
+
+	tests := []struct {
+		name    string
+		token   string
+		key     []byte
+		now     time.Time
+		wantErr string
+	}{
+		{"valid", token, key, now, ""},
+		{"just before expiry", token, key, expires.Add(-time.Second), ""},
+		{"at expiry", token, key, expires, "expired"},
+		{"after expiry", token, key, expires.Add(time.Hour), "expired"},
+		{"other key", token, []byte("Secret"), now, "does not match"},
+		{"tampered ID", "123456." + exp + "." + tag, key, now, "does not match"},
+		{"extended expiry", id + "." + "9999999999" + "." + tag, key, now, "does not match"},
+		{"tampered tag", id + "." + exp + "." + strings.ToUpper(tag), key, now, "does not match"},
+		{"truncated tag", id + "." + exp + "." + tag[1:], key, now, "does not match"},
+		{"no tag", id + "." + exp, key, now, "malformed"},
+		{"extra part", token + ".x", key, now, "malformed"},
+		{"no ID", "." + exp + "." + tag, key, now, "malformed"},
+		{"bad expiry", id + ".soon." + tag, key, now, "malformed"},
+	}
+	for _, tt := range tests {
+		gotID, gotExp, err := ParseToken(tt.token, tt.key, tt.now)
+		if tt.wantErr == "" {
+			if err != nil || gotID != Generate("hello", opts) || !gotExp.Equal(expires) {
+				t.Errorf("%s: ParseToken = %q, %v, %v, want %q, %v", tt.name, gotID, gotExp, err, Generate("hello", opts), expires)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: ParseToken error = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTokenIgnoresPresentation(t *testing.T) {
+	key := []byte("secret")
+	expires := time.Unix(1750000000, 0)
+	plain := Token("hello", expires, key, Options{})
+	if got := Token("hello", expires, key, Options{Spaced: true, NATO: true}); got != plain {
+		t.Errorf("Token with Spaced and NATO = %q, want %q", got, plain)
+	}
+}
@@ -0,0 +1,50 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package goofy
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+// MinWords and MaxWords bound the number of words in a word-based ID.
+const (
+	MinWords = 2
+	MaxWords = 3
+)
+
+//go:embed wordlist.txt
+var wordlistFile string
+
+// wordlist holds 256 short, distinct, easily pronounced English words.
+var wordlist = strings.Fields(wordlistFile)
+
+// encodeWords renders h as n words from the wordlist followed by a
+// two-digit number, joined with hyphens, e.g. "maple-otter-42".
+func encodeWords(h uint64, n int) string {
+	size := uint64(len(wordlist))
+	parts := make([]string, 0, n+1)
+	for i := 0; i < n; i++ {
+		parts = append(parts, wordlist[h%size])
+		h /= size
+	}
+	parts = append(parts, fmt.Sprintf("%02d", h%100))
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,139 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+// batchSize is the number of inputs handed to a pool worker at a time,
+// amortizing the synchronization cost over many cheap hashes.
+const batchSize = 1024
+
+// batch is a run of consecutive inputs and, once done is closed, their
+// records (several per input with -alts) up to the first input whose
+// records could not be generated, if any, and the error it failed with.
+type batch struct {
+	inputs  []string
+	records []record
+	err     error
+	done    chan struct{}
+}
+
+// pool generates records on several goroutines while writing them out
+// in input order. Only a bounded number of batches is in flight, so
+// memory use does not grow with the input.
+type pool struct {
+	e       *emitter
+	cur     *batch      // batch being filled by add
+	work    chan *batch // batches waiting for a worker
+	pending chan *batch // batches waiting to be written, in input order
+	failed  chan struct{}
+	err     error // first write error; set before failed is closed
+	errc    chan error
+}
+
+// newPool starts jobs workers generating records for e.
+func newPool(e *emitter, jobs int) *pool {
+	p := &pool{
+		e:       e,
+		work:    make(chan *batch),
+		pending: make(chan *batch, 2*jobs),
+		failed:  make(chan struct{}),
+		errc:    make(chan error, 1),
+	}
+	for range jobs {
+		go p.worker()
+	}
+	go p.writer()
+	return p
+}
+
+func (p *pool) worker() {
+	for b := range p.work {
+		b.records = make([]record, 0, len(b.inputs))
+		for _, input := range b.inputs {
+			recs, err := p.e.records(input)
+			if err != nil {
+				b.err = err
+				break
+			}
+			b.records = append(b.records, recs...)
+		}
+		close(b.done)
+	}
+}
+
+// writer writes finished batches in order. After the first error, in
+// generating or writing records, it keeps draining so that add and close
+// never block forever.
+func (p *pool) writer() {
+	var err error
+	for b := range p.pending {
+		<-b.done
+		if err != nil {
+			continue
+		}
+		for _, rec := range b.records {
+			if err = p.e.write(rec); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = b.err
+		}
+		if err != nil {
+			p.err = err
+			close(p.failed)
+		}
+	}
+	p.errc <- err
+}
+
+// add queues input; it has the signature processFile expects. It returns
+// the first error, if any, so that reading stops early.
+func (p *pool) add(input string) error {
+	select {
+	case <-p.failed:
+		return p.err
+	default:
+	}
+	if p.cur == nil {
+		p.cur = &batch{inputs: make([]string, 0, batchSize), done: make(chan struct{})}
+	}
+	p.cur.inputs = append(p.cur.inputs, input)
+	if len(p.cur.inputs) == batchSize {
+		p.submit()
+	}
+	return nil
+}
+
+func (p *pool) submit() {
+	b := p.cur
+	p.cur = nil
+	p.pending <- b
+	p.work <- b
+}
+
+// close submits any partial batch, waits until everything is written
+// and stops the workers. It returns the first error.
+func (p *pool) close() error {
+	if p.cur != nil {
+		p.submit()
+	}
+	close(p.work)
+	close(p.pending)
+	return <-p.errc
+}
@@ -0,0 +1,160 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressBar is the width of the bar drawn on a terminal when the input
+// size is known.
+const progressBar = 20
+
+// progress reports on stderr how far a long-running job has got. On a
+// terminal it redraws a single line every second; otherwise, e.g. when
+// stderr goes to a log, it logs a message every ten seconds.
+type progress struct {
+	records atomic.Int64
+	bytes   atomic.Int64
+	total   int64 // input size in bytes, 0 if unknown
+	start   time.Time
+	tty     bool
+	stopc   chan struct{}
+	done    sync.WaitGroup
+}
+
+// startProgress starts reporting on an input of total bytes, 0 if the
+// size is unknown.
+func startProgress(total int64) *progress {
+	p := &progress{total: total, start: time.Now(), tty: isTerminal(os.Stderr), stopc: make(chan struct{})}
+	interval := 10 * time.Second
+	if p.tty {
+		interval = time.Second
+	}
+	p.done.Go(func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				p.report(false)
+			case <-p.stopc:
+				return
+			}
+		}
+	})
+	return p
+}
+
+// wrap returns emit counting the records passed through it, and their
+// bytes including the terminator.
+func (p *progress) wrap(emit func(string) error) func(string) error {
+	return func(input string) error {
+		p.records.Add(1)
+		p.bytes.Add(int64(len(input)) + 1)
+		return emit(input)
+	}
+}
+
+// stop stops the periodic reports and prints the final one.
+func (p *progress) stop() {
+	close(p.stopc)
+	p.done.Wait()
+	p.report(true)
+}
+
+// report prints the records processed so far, the throughput and, when
+// the input size is known, the share done and the estimated time left:
+// as a bar on a terminal, and logged otherwise.
+func (p *progress) report(final bool) {
+	n, read := p.records.Load(), p.bytes.Load()
+	elapsed := time.Since(p.start)
+	var rate float64
+	if secs := elapsed.Seconds(); secs > 0 {
+		rate = float64(n) / secs
+	}
+	var frac float64
+	var eta time.Duration
+	if p.total > 0 {
+		frac = min(float64(read)/float64(p.total), 1)
+		if read > 0 && read < p.total {
+			eta = time.Duration(float64(elapsed) * float64(p.total-read) / float64(read)).Round(time.Second)
+		}
+	}
+
+	if !p.tty {
+		attrs := []any{"records", n, "records_per_sec", int64(rate)}
+		if p.total > 0 {
+			attrs = append(attrs, "bytes", read, "total_bytes", p.total, "percent", math.Round(1000*frac)/10)
+		}
+		if final {
+			slog.Info("done", append(attrs, "elapsed", elapsed.Round(time.Millisecond).String())...)
+		} else {
+			slog.Info("progress", append(attrs, "eta", eta.String())...)
+		}
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("goofy: ")
+	if p.total > 0 {
+		filled := int(frac * progressBar)
+		fmt.Fprintf(&b, "[%s%s] %5.1f%% ", strings.Repeat("#", filled), strings.Repeat(".", progressBar-filled), 100*frac)
+	}
+	fmt.Fprintf(&b, "%d records", n)
+	if p.total > 0 {
+		fmt.Fprintf(&b, ", %.1f of %.1f MB", float64(read)/1e6, float64(p.total)/1e6)
+	}
+	fmt.Fprintf(&b, ", %.0f records/s", rate)
+	switch {
+	case final:
+		fmt.Fprintf(&b, ", took %s\n", elapsed.Round(time.Millisecond))
+	case eta > 0:
+		fmt.Fprintf(&b, ", ETA %s", eta)
+	}
+	// Clear what is left of a longer previous line.
+	fmt.Fprintf(os.Stderr, "\r%s\x1b[K", b.String())
+}
+
+// inputSize returns the combined size of the named files, "-" meaning
+// stdin, or 0 if any of them is not a regular file, e.g. a pipe.
+func inputSize(files []string) int64 {
+	var total int64
+	for _, name := range files {
+		var fi os.FileInfo
+		var err error
+		if name == "-" {
+			fi, err = os.Stdin.Stat()
+		} else {
+			fi, err = os.Stat(name)
+		}
+		if err != nil || !fi.Mode().IsRegular() {
+			return 0
+		}
+		total += fi.Size()
+	}
+	return total
+}
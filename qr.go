@@ -0,0 +1,85 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// qrSize is the width and height in pixels of the PNGs written by -qr.
+const qrSize = 256
+
+// qrWriter renders the bare ID of each record as a QR code, either as
+// Unicode blocks on w or, if file is set, as a PNG image in file.
+type qrWriter struct {
+	w       *bufio.Writer
+	file    string
+	opts    goofy.Options
+	echo    bool // print each record's input above its code
+	written bool // file holds a code already
+}
+
+// newQRWriter returns a recordWriter for records generated under opts
+// that writes to dest: a PNG file, or w if dest is "-".
+func newQRWriter(w io.Writer, dest string, opts goofy.Options, echo bool) *qrWriter {
+	q := &qrWriter{w: bufio.NewWriter(w), opts: opts, echo: echo}
+	if dest != "-" {
+		q.file = dest
+	}
+	return q
+}
+
+func (q *qrWriter) Write(rec record) error {
+	// Labels and scanners expect the bare code, not the spaced form.
+	bare := rec.options(q.opts)
+	bare.Spaced = false
+	bare.NATO = false
+	code, err := qrcode.New(goofy.Generate(rec.data, bare), qrcode.Medium)
+	if err != nil {
+		return err
+	}
+
+	if q.file != "" {
+		if q.written {
+			return errors.New("-qr FILE holds a single ID; use -qr - for several")
+		}
+		q.written = true
+		return code.WriteFile(qrSize, q.file)
+	}
+
+	if q.echo {
+		q.w.WriteString(rec.Input)
+		q.w.WriteByte('\t')
+	}
+	q.w.WriteString(rec.ID)
+	q.w.WriteByte('\n')
+	// Light blocks on the terminal's dark background form the code.
+	_, err = q.w.WriteString(code.ToSmallString(false))
+	return err
+}
+
+func (q *qrWriter) Flush() error {
+	return q.w.Flush()
+}
@@ -0,0 +1,195 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// limiterIdle is how long a client's bucket is kept after its last
+// request; an idle bucket has refilled completely anyway.
+const limiterIdle = 10 * time.Minute
+
+// rateLimiter keeps a token bucket per client: per API key label for
+// requests with an accepted key, else per IP address.
+type rateLimiter struct {
+	limit rate.Limit
+	burst int
+	keys  apiKeys // nil unless -api-keys-file
+
+	mu        sync.Mutex
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+type clientBucket struct {
+	lim      *rate.Limiter
+	lastSeen time.Time
+}
+
+// newRateLimiter returns a limiter refilling each client's bucket with
+// perSec tokens per second up to burst. It returns nil, which allows
+// everything, if perSec is not positive.
+func newRateLimiter(perSec float64, burst int) *rateLimiter {
+	if perSec <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limit:     rate.Limit(perSec),
+		burst:     max(burst, 1),
+		clients:   make(map[string]*clientBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// allow takes a token from client's bucket. If none is left it returns
+// false and how long until one will be.
+func (l *rateLimiter) allow(client string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	now := time.Now()
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) > limiterIdle {
+		for k, b := range l.clients {
+			if now.Sub(b.lastSeen) > limiterIdle {
+				delete(l.clients, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.clients[client]
+	if !ok {
+		b = &clientBucket{lim: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[client] = b
+	}
+	b.lastSeen = now
+	l.mu.Unlock()
+
+	r := b.lim.ReserveN(now, 1)
+	if d := r.DelayFrom(now); d > 0 {
+		r.CancelAt(now)
+		return false, d
+	}
+	return true, 0
+}
+
+// retryAfter renders d in whole seconds, rounded up, for Retry-After.
+func retryAfter(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// limitHTTP wraps h, answering 429 Too Many Requests with a Retry-After
+// header to clients that exhausted their bucket.
+func (l *rateLimiter) limitHTTP(h http.HandlerFunc) http.HandlerFunc {
+	if l == nil {
+		return h
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, wait := l.allow(l.httpBucket(r)); !ok {
+			w.Header().Set("Retry-After", retryAfter(wait))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		h(w, r)
+	}
+}
+
+// httpBucket names the bucket of r's client: the label of its API key,
+// or else its IP address.
+func (l *rateLimiter) httpBucket(r *http.Request) string {
+	if label, ok := l.keys.label(httpAPIKey(r)); ok {
+		return "key:" + label
+	}
+	return httpClient(r)
+}
+
+// grpcBucket names the bucket of the client of a gRPC call: the label of
+// its API key, or else its IP address.
+func (l *rateLimiter) grpcBucket(ctx context.Context) string {
+	if label, ok := l.keys.label(grpcAPIKey(ctx)); ok {
+		return "key:" + label
+	}
+	return grpcClient(ctx)
+}
+
+// httpClient identifies the client of r by its IP address.
+func httpClient(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// grpcClient identifies the client of a gRPC call by its IP address.
+func grpcClient(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// checkGRPC returns a ResourceExhausted error, with a retry-after header,
+// if the client of ctx exhausted its bucket.
+func (l *rateLimiter) checkGRPC(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	ok, wait := l.allow(l.grpcBucket(ctx))
+	if ok {
+		return nil
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("retry-after", retryAfter(wait)))
+	return status.Errorf(codes.ResourceExhausted, "rate limit exceeded, retry after %s", wait.Round(time.Millisecond))
+}
+
+// unaryInterceptor rate limits unary gRPC calls.
+func (l *rateLimiter) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := l.checkGRPC(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor rate limits streaming gRPC calls.
+func (l *rateLimiter) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.checkGRPC(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
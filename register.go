@@ -0,0 +1,228 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/al-maisan/goofy/internal/registry"
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// defaultRegistry is the registry database used when -registry is not given.
+const defaultRegistry = "goofy.db"
+
+// addRegistryFlag registers the -registry flag on fs.
+func addRegistryFlag(fs *flag.FlagSet) *string {
+	return fs.String("registry", defaultRegistry, "registry database `PATH` (SQLite), bolt:PATH for a pure-Go bbolt file, or a redis://host:6379/0 URL to share one registry between instances")
+}
+
+// registerCommand defines the flags of "goofy register" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func registerCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	plain := fs.Bool("plain", false, "output as plain 6-digit string")
+	path := addRegistryFlag(fs)
+	unique := fs.Bool("unique", false, "on collision, probe alternative IDs until a free one is found")
+	ttl := addTTLFlag(fs, "registry entries", 0)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s register [options] <string>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Generate IDs and record the input-to-ID assignments in a registry,\n")
+		fmt.Fprintf(os.Stderr, "refusing inputs whose ID already belongs to a different input.\n")
+		fmt.Fprintf(os.Stderr, "With -unique, colliding inputs are instead assigned the first free\n")
+		fmt.Fprintf(os.Stderr, "alternative ID (the hash with an incrementing counter mixed in).\n")
+		fmt.Fprintf(os.Stderr, "With -ttl, entries lapse after that long and \"goofy registry gc\"\n")
+		fmt.Fprintf(os.Stderr, "purges them; a lapsed ID can be registered to any input again.\n")
+		fmt.Fprintf(os.Stderr, "With -namespace, IDs are registered in that namespace, where they never\n")
+		fmt.Fprintf(os.Stderr, "conflict with the IDs of other namespaces in the same registry.\n")
+		fmt.Fprintf(os.Stderr, "If no <string> is given, inputs are read from stdin, one per line.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - success\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed input\n")
+		fmt.Fprintf(os.Stderr, "  4 - ID already registered to another input\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O or registry error\n")
+	}
+
+	return func() int {
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		if fs.NArg() < 1 && isTerminal(os.Stdin) {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument <string>\n\n")
+			fs.Usage()
+			return 1
+		}
+
+		reg, err := registry.Open(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: opening registry: %v\n", err)
+			return 5
+		}
+		defer reg.Close()
+
+		ctx := context.Background()
+		conflicts := 0
+		// IDs are registered in digits, so that they are looked up the
+		// same with and without -nato.
+		key := opts
+		key.NATO = false
+		register := func(input string) error {
+			opts := opts
+			var id string
+			var err error
+			if *unique {
+				id, opts.Counter, err = registerUnique(ctx, reg, input, key, time.Duration(*ttl))
+			} else if id, err = goofy.TryGenerate(input, key); err == nil {
+				err = reg.Register(ctx, opts.Namespace, id, input, time.Duration(*ttl))
+			}
+			var conflict *registry.ConflictError
+			if errors.As(err, &conflict) {
+				conflicts++
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return nil
+			}
+			if err != nil {
+				return ioError{err}
+			}
+			if !*plain || opts.NATO {
+				opts.Spaced = !*plain
+				if id, err = goofy.TryGenerate(input, opts); err != nil {
+					return ioError{err}
+				}
+			}
+			fmt.Println(id)
+			return nil
+		}
+
+		if fs.NArg() > 0 {
+			for _, input := range fs.Args() {
+				if err = register(input); err != nil {
+					break
+				}
+			}
+		} else {
+			err = processFile("-", inputOptions{}, register)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		if conflicts > 0 {
+			return 4
+		}
+		return 0
+	}
+}
+
+// maxProbes bounds the number of alternative IDs tried by -unique.
+const maxProbes = 1000
+
+// registerUnique registers input under the first of its candidate IDs
+// (see goofy.Options.Counter) that is free or already assigned to it, and
+// returns that ID and its counter, to expire after ttl if positive.
+// Probing is deterministic: the same registry contents always yield the
+// same assignment.
+func registerUnique(ctx context.Context, reg registry.Registry, input string, opts goofy.Options, ttl time.Duration) (string, int, error) {
+	var err error
+	for n := 0; n < maxProbes; n++ {
+		opts.Counter = n
+		var id string
+		if id, err = goofy.TryGenerate(input, opts); err != nil {
+			return "", 0, err
+		}
+		err = reg.Register(ctx, opts.Namespace, id, input, ttl)
+		var conflict *registry.ConflictError
+		if !errors.As(err, &conflict) {
+			return id, n, err
+		}
+	}
+	return "", 0, fmt.Errorf("no free ID for %q after %d probes: %w", input, maxProbes, err)
+}
+
+// lookupCommand defines the flags of "goofy lookup" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func lookupCommand(fs *flag.FlagSet) func() int {
+	path := addRegistryFlag(fs)
+	namespace := fs.String("namespace", "", "look the ID up in namespace `NAME`")
+	sep := fs.String("sep", goofy.DefaultSep, "ignore `SEP` between groups of the ID besides spaces, e.g. \"-\" for 259-144")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s lookup [options] <ID>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print the registered input(s) that produced an ID.\n")
+		fmt.Fprintf(os.Stderr, "The ID may be given plain (259144), spaced (\"25 91 44\") or grouped\n")
+		fmt.Fprintf(os.Stderr, "as by register -sep (\"259-144\" with -sep -).\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - success\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or ID not registered\n")
+		fmt.Fprintf(os.Stderr, "  5 - registry error\n")
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one <ID>\n\n")
+			fs.Usage()
+			return 1
+		}
+		id := strings.ReplaceAll(fs.Arg(0), " ", "")
+		if *sep != "" {
+			id = strings.ReplaceAll(id, *sep, "")
+		}
+
+		reg, err := registry.Open(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: opening registry: %v\n", err)
+			return 5
+		}
+		defer reg.Close()
+
+		entries, err := reg.Lookup(context.Background(), *namespace, id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 5
+		}
+		if len(entries) == 0 {
+			where := ""
+			if *namespace != "" {
+				where = fmt.Sprintf(" in namespace %q", *namespace)
+			}
+			fmt.Fprintf(os.Stderr, "Error: ID %s is not registered%s\n", id, where)
+			return 1
+		}
+		for _, e := range entries {
+			fmt.Println(e.Input)
+		}
+		return 0
+	}
+}
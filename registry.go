@@ -0,0 +1,370 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/al-maisan/goofy/internal/registry"
+)
+
+// registryCommands lists the subcommands of "goofy registry".
+var registryCommands = []command{
+	{"export", "write every registry entry to a JSON dump", registryExportCommand},
+	{"import", "register the entries of a JSON dump", registryImportCommand},
+	{"gc", "purge the entries that have expired", registryGCCommand},
+}
+
+// dumpVersion is the version of the registry dump format.
+const dumpVersion = 1
+
+// registryDump is a registry export: {"version":1,"entries":[...]}.
+type registryDump struct {
+	Version int         `json:"version"`
+	Entries []dumpEntry `json:"entries"`
+}
+
+// dumpEntry is one entry of a registryDump.
+type dumpEntry struct {
+	Namespace string     `json:"namespace,omitempty"`
+	ID        string     `json:"id"`
+	Input     string     `json:"input"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // with -ttl
+}
+
+// registryCommand defines the flags of "goofy registry" on fs and returns
+// the function running it once they are parsed, which runs the
+// subcommand named by the first argument and returns its exit code.
+func registryCommand(fs *flag.FlagSet) func() int {
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s registry <command> [options] [arguments]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Maintain the registry of \"goofy register\".\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		printCommands(registryCommands)
+		fmt.Fprintf(os.Stderr, "\nRun \"%s registry <command> -h\" for the options of a command.\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() < 1 {
+			fmt.Fprintf(os.Stderr, "Error: missing registry command\n\n")
+			fs.Usage()
+			return 1
+		}
+		for _, c := range registryCommands {
+			if fs.Arg(0) == c.name {
+				return runCommand("registry "+c.name, c.setup, fs.Args()[1:])
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Error: unknown registry command %q\n\n", fs.Arg(0))
+		fs.Usage()
+		return 1
+	}
+}
+
+// registryExportCommand defines the flags of "goofy registry export" on
+// fs and returns the function running it once they are parsed, which
+// returns the process exit code.
+func registryExportCommand(fs *flag.FlagSet) func() int {
+	path := addRegistryFlag(fs)
+	out := fs.String("o", "-", "write the dump to `FILE` (\"-\" for stdout)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s registry export [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Write every entry of a registry, in order of namespace and ID, to a\n")
+		fmt.Fprintf(os.Stderr, "JSON dump that \"goofy registry import\" loads into another registry of\n")
+		fmt.Fprintf(os.Stderr, "any kind, to back it up, move it to another machine or seed a test\n")
+		fmt.Fprintf(os.Stderr, "environment.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s registry export -o dump.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s registry export -registry redis://localhost:6379/0 | gzip > dump.json.gz\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - success\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O or registry error\n")
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+
+		reg, err := registry.Open(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: opening registry: %v\n", err)
+			return 5
+		}
+		defer reg.Close()
+
+		w := os.Stdout
+		if *out != "-" {
+			if w, err = os.Create(*out); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 5
+			}
+		}
+		n, err := writeDump(context.Background(), w, reg)
+		if w != os.Stdout {
+			if cerr := w.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 5
+		}
+		fmt.Fprintf(os.Stderr, "exported %d entries\n", n)
+		return 0
+	}
+}
+
+// writeDump writes the entries of reg to w as a registryDump, one entry
+// per line so that dumps diff well, and returns how many it wrote.
+func writeDump(ctx context.Context, w io.Writer, reg registry.Registry) (int, error) {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "{\"version\":%d,\"entries\":[", dumpVersion)
+	n := 0
+	err := reg.Walk(ctx, func(e registry.Entry) error {
+		d := dumpEntry{Namespace: e.Namespace, ID: e.ID, Input: e.Input, CreatedAt: e.CreatedAt.UTC()}
+		if !e.ExpiresAt.IsZero() {
+			expires := e.ExpiresAt.UTC()
+			d.ExpiresAt = &expires
+		}
+		line, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			bw.WriteByte(',')
+		}
+		bw.WriteByte('\n')
+		bw.Write(line)
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	bw.WriteString("\n]}\n")
+	return n, bw.Flush()
+}
+
+// registryImportCommand defines the flags of "goofy registry import" on
+// fs and returns the function running it once they are parsed, which
+// returns the process exit code.
+func registryImportCommand(fs *flag.FlagSet) func() int {
+	path := addRegistryFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "only report what would be imported and which entries conflict")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s registry import [options] <FILE>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Register the entries of a dump written by \"goofy registry export\"\n")
+		fmt.Fprintf(os.Stderr, "(\"-\" reads stdin), keeping their creation times. Entries already\n")
+		fmt.Fprintf(os.Stderr, "present are skipped; an entry whose ID belongs to a different input\n")
+		fmt.Fprintf(os.Stderr, "is reported as a conflict and not imported, while the others are.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s registry import dump.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s registry import -dry-run -registry bolt:goofy.bolt dump.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - success\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed dump\n")
+		fmt.Fprintf(os.Stderr, "  4 - some entries conflict with the registry\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O or registry error\n")
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one <FILE>\n\n")
+			fs.Usage()
+			return 1
+		}
+		dump, err := readDump(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+
+		reg, err := registry.Open(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: opening registry: %v\n", err)
+			return 5
+		}
+		defer reg.Close()
+
+		ctx := context.Background()
+		added, present, conflicts := 0, 0, 0
+		pending := make(map[entryKey]string) // -dry-run: the inputs of IDs that would be added
+		for _, d := range dump.Entries {
+			e := registry.Entry{Namespace: d.Namespace, ID: d.ID, Input: d.Input, CreatedAt: d.CreatedAt}
+			if d.ExpiresAt != nil {
+				e.ExpiresAt = *d.ExpiresAt
+			}
+			var ok bool
+			if *dryRun {
+				ok, err = checkImport(ctx, reg, e, pending)
+			} else {
+				ok, err = reg.Import(ctx, e)
+			}
+			var conflict *registry.ConflictError
+			switch {
+			case errors.As(err, &conflict):
+				conflicts++
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			case err != nil:
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return 5
+			case ok:
+				added++
+			default:
+				present++
+			}
+		}
+		verb := "imported"
+		if *dryRun {
+			verb = "would import"
+		}
+		fmt.Fprintf(os.Stderr, "%s %d of %d entries (%d already present, %d conflicting)\n",
+			verb, added, len(dump.Entries), present, conflicts)
+		if conflicts > 0 {
+			return 4
+		}
+		return 0
+	}
+}
+
+// registryGCCommand defines the flags of "goofy registry gc" on fs and
+// returns the function running it once they are parsed, which returns
+// the process exit code.
+func registryGCCommand(fs *flag.FlagSet) func() int {
+	path := addRegistryFlag(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s registry gc [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Delete the registry entries whose -ttl has run out. Expired entries\n")
+		fmt.Fprintf(os.Stderr, "are ignored anyway, so this only reclaims their space; run it from\n")
+		fmt.Fprintf(os.Stderr, "cron, say. Redis registries expire entries themselves.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s registry gc -registry codes.db\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - success\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  5 - registry error\n")
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+
+		reg, err := registry.Open(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: opening registry: %v\n", err)
+			return 5
+		}
+		defer reg.Close()
+
+		n, err := reg.Purge(context.Background(), time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 5
+		}
+		fmt.Fprintf(os.Stderr, "purged %d expired entries\n", n)
+		return 0
+	}
+}
+
+// readDump reads and checks the registryDump in the named file, or stdin
+// for "-".
+func readDump(name string) (*registryDump, error) {
+	r := io.Reader(os.Stdin)
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	var dump registryDump
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&dump); err != nil {
+		return nil, fmt.Errorf("reading dump %s: %w", name, err)
+	}
+	if dump.Version != dumpVersion {
+		return nil, fmt.Errorf("dump %s has version %d, want %d", name, dump.Version, dumpVersion)
+	}
+	for i, e := range dump.Entries {
+		if e.ID == "" {
+			return nil, fmt.Errorf("dump %s: entry %d has no id", name, i+1)
+		}
+	}
+	return &dump, nil
+}
+
+// entryKey identifies a registry entry: its ID within its namespace.
+type entryKey struct {
+	namespace, id string
+}
+
+// checkImport reports what importing e into reg would do, without
+// changing it: whether e would be added, or a *registry.ConflictError.
+// pending holds the inputs of the IDs that earlier entries would add, and
+// gains e's if e would be added.
+func checkImport(ctx context.Context, reg registry.Registry, e registry.Entry, pending map[entryKey]string) (bool, error) {
+	key := entryKey{e.Namespace, e.ID}
+	if input, ok := pending[key]; ok {
+		if input != e.Input {
+			return false, &registry.ConflictError{Namespace: e.Namespace, ID: e.ID, Input: e.Input, Existing: input}
+		}
+		return false, nil
+	}
+	entries, err := reg.Lookup(ctx, e.Namespace, e.ID)
+	if err != nil {
+		return false, err
+	}
+	for _, existing := range entries {
+		if existing.Input != e.Input {
+			return false, &registry.ConflictError{Namespace: e.Namespace, ID: e.ID, Input: e.Input, Existing: existing.Input}
+		}
+	}
+	if len(entries) > 0 {
+		return false, nil
+	}
+	pending[key] = e.Input
+	return true, nil
+}
@@ -0,0 +1,356 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"golang.org/x/term"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// replPrompt is the prompt of "goofy repl" on a terminal.
+const replPrompt = "goofy> "
+
+// replCommands lists the commands of "goofy repl" besides :NAME [VALUE]
+// for the generation flags.
+var replCommands = []struct{ name, summary string }{
+	{":help", "list the commands"},
+	{":flags", "print the generation flags in effect as command line options"},
+	{":reset", "restore the generation flags the REPL started with"},
+	{":quit", "leave the REPL (or Ctrl-D)"},
+}
+
+// replCommand defines the flags of "goofy repl" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func replCommand(fs *flag.FlagSet) func() int {
+	before := flagNames(fs)
+	gen := addGenFlags(fs)
+	var genNames []string
+	for _, name := range flagNames(fs) {
+		if !slices.Contains(before, name) {
+			genNames = append(genNames, name)
+		}
+	}
+	plain := fs.Bool("plain", false, "output IDs as plain strings instead of in spaced groups")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s repl [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Read strings interactively and print the ID of each line entered,\n")
+		fmt.Fprintf(os.Stderr, "with line editing and history on a terminal. An ID that an earlier\n")
+		fmt.Fprintf(os.Stderr, "line already had is reported as a collision. Lines starting with a\n")
+		fmt.Fprintf(os.Stderr, "colon are commands; \"::\" starts a string beginning with a colon.\n")
+		fmt.Fprintf(os.Stderr, "When stdin is not a terminal, lines are read from it without prompts.\n\n")
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", ":NAME VALUE", "set the generation flag -NAME, e.g. :algo sha256, :digits 8 or :salt pepper")
+		fmt.Fprintf(os.Stderr, "  %-13s %s\n", ":NAME", "print the value of -NAME")
+		for _, c := range replCommands {
+			fmt.Fprintf(os.Stderr, "  %-13s %s\n", c.name, c.summary)
+		}
+		fmt.Fprintf(os.Stderr, "\nOptions:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s repl -digits 4\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  printf '128\\n:digits 8\\n128\\n' | %s repl\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - success\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O error\n")
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		r := &repl{fs: fs, gen: gen, names: genNames, spaced: !*plain, initial: make(map[string]string)}
+		for _, name := range genNames {
+			r.initial[name] = fs.Lookup(name).Value.String()
+		}
+		r.use(opts)
+		if isTerminal(os.Stdin) && isTerminal(os.Stdout) {
+			err = r.runTerminal()
+		} else {
+			err = r.run(newScanLines(os.Stdin), os.Stdout, os.Stderr)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 5
+		}
+		return 0
+	}
+}
+
+// flagNames returns the names of the flags defined on fs, in
+// lexicographical order.
+func flagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+	return names
+}
+
+// repl is the state of a "goofy repl" session.
+type repl struct {
+	fs      *flag.FlagSet
+	gen     *genFlags
+	names   []string          // the generation flags, settable with :NAME VALUE
+	initial map[string]string // their values at the start, for :reset
+	spaced  bool              // print IDs in spaced groups (no -plain)
+	opts    goofy.Options
+	// collisions remembers the IDs printed under opts; it starts afresh
+	// whenever they change.
+	collisions *collisionDetector
+}
+
+// lineReader reads the lines of a REPL session; *term.Terminal is one.
+type lineReader interface {
+	ReadLine() (string, error)
+}
+
+// scanLines reads lines without prompting, for stdin that is not a
+// terminal.
+type scanLines struct {
+	s *bufio.Scanner
+}
+
+func newScanLines(r io.Reader) *scanLines {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, defaultMaxRecord)
+	return &scanLines{s}
+}
+
+func (l *scanLines) ReadLine() (string, error) {
+	if !l.s.Scan() {
+		return "", cmp.Or(l.s.Err(), io.EOF)
+	}
+	return strings.TrimSuffix(l.s.Text(), "\r"), nil
+}
+
+// runTerminal runs the session on the terminal of stdin and stdout, which
+// it puts into raw mode for line editing until the session ends.
+func (r *repl) runTerminal() error {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, replPrompt)
+	t.AutoCompleteCallback = r.complete
+	return r.run(t, t, t)
+}
+
+// run reads lines from in until it ends or :quit, writing IDs to out and
+// errors and collisions to errw.
+func (r *repl) run(in lineReader, out, errw io.Writer) error {
+	for {
+		line, err := in.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if quit := r.eval(line, out, errw); quit {
+			return nil
+		}
+	}
+}
+
+// eval evaluates a line, reporting whether it asks to quit. Empty lines
+// are skipped.
+func (r *repl) eval(line string, out, errw io.Writer) bool {
+	if line == "" {
+		return false
+	}
+	if line[0] != ':' || strings.HasPrefix(line, "::") {
+		r.generate(strings.TrimPrefix(line, ":"), out, errw)
+		return false
+	}
+	name, value, hasValue := strings.Cut(strings.TrimSpace(line[1:]), " ")
+	value = strings.TrimSpace(value)
+	switch name {
+	case "help":
+		r.help(out)
+	case "flags":
+		fmt.Fprintln(out, r.flags())
+	case "reset":
+		// Flags that were not changed are left alone: setting one marks
+		// it as given, which some depend on (see genFlags.options).
+		for _, name := range r.names {
+			if r.fs.Lookup(name).Value.String() != r.initial[name] {
+				r.fs.Set(name, r.initial[name])
+			}
+		}
+		opts, err := r.gen.options()
+		if err != nil {
+			fmt.Fprintf(errw, "Error: %v\n", err) // cannot happen: the initial flags were valid
+			return false
+		}
+		r.use(opts)
+	case "quit", "q", "exit":
+		return true
+	default:
+		if !slices.Contains(r.names, name) {
+			fmt.Fprintf(errw, "Error: unknown command :%s (try :help)\n", name)
+			return false
+		}
+		if !hasValue {
+			fmt.Fprintf(out, "%s = %s\n", name, r.value(name))
+			return false
+		}
+		if err := r.set(name, value); err != nil {
+			fmt.Fprintf(errw, "Error: %v\n", err)
+		}
+	}
+	return false
+}
+
+// generate prints the ID of input, and on errw the earlier input it
+// collides with, if any, or why there is none.
+func (r *repl) generate(input string, out, errw io.Writer) {
+	id, err := goofy.TryGenerate(input, r.opts)
+	if err != nil {
+		fmt.Fprintf(errw, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, id)
+	if first, ok := r.collisions.check(input, id); ok {
+		fmt.Fprintf(errw, "collision: %q has the same ID as %q\n", input, first)
+	}
+}
+
+// set sets the generation flag name to value, keeping the previous value
+// if the resulting options are invalid.
+func (r *repl) set(name, value string) error {
+	f := r.fs.Lookup(name)
+	old := f.Value.String()
+	if err := r.fs.Set(name, value); err != nil {
+		return fmt.Errorf("invalid value %q for -%s: %v", value, name, err)
+	}
+	opts, err := r.gen.options()
+	if err != nil {
+		r.fs.Set(name, old)
+		return err
+	}
+	r.use(opts)
+	return nil
+}
+
+// use makes opts the options of the following IDs, forgetting the IDs
+// generated so far.
+func (r *repl) use(opts goofy.Options) {
+	opts.Spaced = r.spaced
+	r.opts = opts
+	r.collisions = newCollisionDetector()
+}
+
+// value returns the value of the generation flag name for display; keys
+// are not shown.
+func (r *repl) value(name string) string {
+	v := r.fs.Lookup(name).Value.String()
+	if (name == "key" || name == "hmac-key") && v != "" {
+		return "(set)"
+	}
+	return v
+}
+
+// flags returns the generation flags that differ from their defaults as
+// command line options, leaving keys out like git-hook does.
+func (r *repl) flags() string {
+	var args []string
+	for _, name := range r.names {
+		f := r.fs.Lookup(name)
+		if name == "key" || name == "hmac-key" || f.Value.String() == f.DefValue {
+			continue
+		}
+		args = append(args, shellQuote("-"+name+"="+f.Value.String()))
+	}
+	if len(args) == 0 {
+		return "(defaults)"
+	}
+	return strings.Join(args, " ")
+}
+
+// help prints the commands of the REPL.
+func (r *repl) help(w io.Writer) {
+	fmt.Fprintf(w, "Enter a string to print its ID, or a command:\n")
+	fmt.Fprintf(w, "  %-13s %s\n", ":NAME VALUE", "set the generation flag -NAME")
+	fmt.Fprintf(w, "  %-13s %s\n", ":NAME", "print the value of -NAME")
+	for _, c := range replCommands {
+		fmt.Fprintf(w, "  %-13s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "Generation flags: %s\n", strings.Join(r.names, ", "))
+}
+
+// complete completes the command or flag name being typed at the start
+// of the line on Tab, if only one matches.
+func (r *repl) complete(line string, pos int, key rune) (string, int, bool) {
+	if key != '\t' || !strings.HasPrefix(line, ":") || strings.Contains(line[:pos], " ") {
+		return "", 0, false
+	}
+	var match string
+	for _, name := range r.completions() {
+		if strings.HasPrefix(name, line[:pos]) {
+			if match != "" {
+				return "", 0, false
+			}
+			match = name
+		}
+	}
+	if match == "" {
+		return "", 0, false
+	}
+	match += " "
+	return match + line[pos:], len(match), true
+}
+
+// completions returns the commands of the REPL, including a :NAME for
+// every generation flag.
+func (r *repl) completions() []string {
+	var names []string
+	for _, c := range replCommands {
+		names = append(names, c.name)
+	}
+	for _, name := range r.names {
+		names = append(names, ":"+name)
+	}
+	return names
+}
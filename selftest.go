@@ -0,0 +1,283 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+	"text/tabwriter"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// hashVectors are known answers of the hash algorithms over whole inputs,
+// guarding against accidental changes to the hashes.
+var hashVectors = []struct {
+	algo, key, input string
+	sum              uint64
+}{
+	{"fnv1a", "", "", 1469598103934665603},
+	{"fnv1a", "", "hello world!", 4677444672243259144},
+	{"fnv1a", "", "The quick brown fox jumps over the lazy dog", 10881670201689006430},
+	{"fnv1", "", "hello world!", 5850291033509667426},
+	{"fnv1", "", "The quick brown fox jumps over the lazy dog", 9717426912667080236},
+	{"xxhash64", "", "", 17241709254077376921},
+	{"xxhash64", "", "hello world!", 11221175996223801097},
+	{"xxhash64", "", "The quick brown fox jumps over the lazy dog", 802816344064684476},
+	{"sha256", "", "", 16406829232824261652},
+	{"sha256", "", "hello world!", 8433524379836965586},
+	{"siphash", "", "", 2202906307356721367},
+	{"siphash", "", "hello world!", 10941343343139420790},
+	// The SipHash-2-4 reference vector: key 00..0f, input 00..0e.
+	{"siphash", "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e\x0f", "\x00\x01\x02\x03\x04\x05\x06\x07\x08\x09\x0a\x0b\x0c\x0d\x0e", 0xa129ca6149be45e5},
+	{"hmac-sha256", "secret", "hello world!", 8216420810100382534},
+	// The BLAKE3 reference digest of "" starts with af1349b9f5f9a1a6.
+	{"blake3", "", "", 0xaf1349b9f5f9a1a6},
+	// The standard check values of the CRCs.
+	{"crc32", "", "123456789", 0xcbf43926},
+	{"crc64", "", "123456789", 0x995dc9bbdf1939fa},
+}
+
+// idVectors are known IDs, covering truncation and rendering.
+var idVectors = []struct {
+	input string
+	opts  goofy.Options
+	id    string
+}{
+	{"hello world!", goofy.Options{}, "259144"},
+	{"hello world!", goofy.Options{Spaced: true}, "25 91 44"},
+	{"hello world! and a lot more text beyond 32 bytes", goofy.Options{}, "008889"},
+	{"hello world!", goofy.Options{Base: 36}, "2B1FD4"},
+	{"hello world!", goofy.Options{Words: 2}, "arrow-dragon-74"},
+	{"hello world!", goofy.Options{CheckDigit: "luhn"}, "2591444"},
+	{"123", goofy.Options{Namespace: "orders", Salt: "s"}, "917245"},
+	// SHA-256 of "orders\x00123" starts with 6b174fa848fd936e.
+	{"123", goofy.Options{Algo: "sha256", Namespace: "orders"}, "312174"},
+	{"123", goofy.Options{Namespace: "orders", Salt: "s", Construction: 2}, "393775"},
+	// SHA-256 of "goofy2n\x06ordersi\x03123" starts with f602bb994ca1e685.
+	{"123", goofy.Options{Algo: "sha256", Namespace: "orders", Construction: 2}, "762693"},
+}
+
+// selftestCommand defines the flags of "goofy selftest" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func selftestCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	avalanche := fs.Bool("avalanche", false, "also measure how IDs change when single bits or characters of inputs change")
+	samples := fs.Int("samples", 1000, "run the avalanche test on at most `N` inputs")
+	nul := fs.Bool("0", false, "read NUL-separated records from FILEs instead of lines")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s selftest [options] [FILE...]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Check the hash algorithms and ID rendering against known answers. With\n")
+		fmt.Fprintf(os.Stderr, "-avalanche, also flip every bit and change every character of sample\n")
+		fmt.Fprintf(os.Stderr, "inputs within the hashed prefix, one at a time, and report how much the\n")
+		fmt.Fprintf(os.Stderr, "hash and the ID change compared to an ideal hash. The samples are the\n")
+		fmt.Fprintf(os.Stderr, "records of each FILE, to test your input patterns, or synthetic ones.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s selftest\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s selftest -avalanche -algo xxhash64 skus.txt\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - all known answers match\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage or malformed input\n")
+		fmt.Fprintf(os.Stderr, "  2 - a known answer does not match\n")
+		fmt.Fprintf(os.Stderr, "  5 - I/O error\n")
+	}
+
+	return func() int {
+		opts, err := gen.options()
+		if err == nil && *samples < 1 {
+			err = fmt.Errorf("-samples must be positive, got %d", *samples)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		code := 0
+		if failed := knownAnswers(os.Stderr); failed > 0 {
+			fmt.Printf("known answers: %d of %d FAILED\n", failed, len(hashVectors)+len(idVectors))
+			code = 2
+		} else {
+			fmt.Printf("known answers: %d passed\n", len(hashVectors)+len(idVectors))
+		}
+		if !*avalanche {
+			return code
+		}
+
+		var inputs []string
+		in := inputOptions{nul: *nul, maxRecord: defaultMaxRecord}
+		for _, name := range fs.Args() {
+			err := processFile(name, in, func(s string) error {
+				if len(inputs) < *samples && s != "" {
+					inputs = append(inputs, s)
+				}
+				return nil
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return exitCode(err)
+			}
+		}
+		if len(fs.Args()) == 0 {
+			inputs = syntheticInputs(*samples, 16)
+		}
+		if len(inputs) == 0 {
+			fmt.Fprintf(os.Stderr, "Error: no inputs to test\n")
+			return 1
+		}
+		reportAvalanche(os.Stdout, inputs, opts)
+		return code
+	}
+}
+
+// knownAnswers checks hashVectors and idVectors, reports mismatches on w
+// and returns their number.
+func knownAnswers(w io.Writer) int {
+	failed := 0
+	for _, v := range hashVectors {
+		h, err := goofy.NewHasher(v.algo, []byte(v.key))
+		if err != nil {
+			fmt.Fprintf(w, "%s: %v\n", v.algo, err)
+			failed++
+			continue
+		}
+		if got := h.Sum64([]byte(v.input)); got != v.sum {
+			fmt.Fprintf(w, "%s(%q) = %d, want %d\n", v.algo, v.input, got, v.sum)
+			failed++
+		}
+	}
+	for _, v := range idVectors {
+		if got := goofy.Generate(v.input, v.opts); got != v.id {
+			fmt.Fprintf(w, "ID of %q with %+v = %q, want %q\n", v.input, v.opts, got, v.id)
+			failed++
+		}
+	}
+	return failed
+}
+
+// avalancheStats accumulates how hashes and IDs change under one kind of
+// input change.
+type avalancheStats struct {
+	trials  int
+	flipped int     // hash bits flipped, summed over trials
+	perBit  [64]int // trials flipping each hash bit
+	ids     int     // trials changing the ID
+	symbols int     // ID symbols changed, summed over trials
+	length  int     // ID symbols compared, summed over trials
+}
+
+// add records the change from (sum, id) to (sum2, id2).
+func (a *avalancheStats) add(sum, sum2 uint64, id, id2 string) {
+	a.trials++
+	d := sum ^ sum2
+	a.flipped += bits.OnesCount64(d)
+	for i := range a.perBit {
+		if d&(1<<i) != 0 {
+			a.perBit[i]++
+		}
+	}
+	if id != id2 {
+		a.ids++
+	}
+	for i := 0; i < len(id) && i < len(id2); i++ {
+		if id[i] != id2[i] {
+			a.symbols++
+		}
+	}
+	a.length += min(len(id), len(id2))
+}
+
+// bias returns the largest deviation from one half of the share of
+// trials flipping any single hash bit.
+func (a *avalancheStats) bias() float64 {
+	worst := 0.0
+	for _, k := range a.perBit {
+		worst = math.Max(worst, math.Abs(float64(k)/float64(a.trials)-0.5))
+	}
+	return worst
+}
+
+// reportAvalanche flips each bit and changes each printable ASCII
+// character within the hashed prefix of every input and writes how the
+// hashes and IDs under opts changed.
+func reportAvalanche(w io.Writer, inputs []string, opts goofy.Options) {
+	opts.Spaced = false
+	opts.NATO = false
+	var flips, chars avalancheStats
+	for _, s := range inputs {
+		sum, id := goofy.Sum64(s, opts), goofy.Generate(s, opts)
+		hashed, _ := goofy.HashedInput(s, opts)
+		b := []byte(s)
+		for i := range len(hashed) {
+			c := b[i]
+			for k := range 8 {
+				b[i] = c ^ 1<<k
+				flips.add(sum, goofy.Sum64(string(b), opts), id, goofy.Generate(string(b), opts))
+			}
+			if c > ' ' && c < '~' {
+				b[i] = c + 1
+				chars.add(sum, goofy.Sum64(string(b), opts), id, goofy.Generate(string(b), opts))
+			}
+			b[i] = c
+		}
+	}
+
+	algo := opts.Algo
+	if algo == "" {
+		algo = goofy.DefaultAlgo
+	}
+	fmt.Fprintf(w, "\navalanche of %s over %d inputs:\n", algo, len(inputs))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "\ttrials\thash bits flipped\tworst bit bias\tIDs changed\tsymbols changed\t\n")
+	for _, row := range []struct {
+		name string
+		a    *avalancheStats
+	}{{"bit flips", &flips}, {"char changes", &chars}} {
+		a := row.a
+		if a.trials == 0 {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%.2f%%\t%.2f%%\t%.2f%%\t%.2f%%\t\n", row.name, a.trials,
+			100*float64(a.flipped)/float64(64*a.trials), 100*a.bias(),
+			100*float64(a.ids)/float64(a.trials), 100*float64(a.symbols)/float64(max(a.length, 1)))
+	}
+	// An ideal hash flips each bit with probability one half and changes
+	// each symbol unless it happens to draw the same one again.
+	symbols := "-" // words differ in length, so there is no simple ideal
+	if opts.Words == 0 {
+		base := opts.Base
+		if base == 0 {
+			base = goofy.DefaultBase
+		}
+		if opts.Alphabet != "" {
+			base = len(opts.Alphabet)
+		}
+		symbols = fmt.Sprintf("%.2f%%", 100*(1-1/float64(base)))
+	}
+	fmt.Fprintf(tw, "ideal\t\t50.00%%\t0.00%%\t%.2f%%\t%s\t\n", 100*(1-1/float64(opts.Space())), symbols)
+	tw.Flush()
+}
@@ -0,0 +1,565 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/al-maisan/goofy/internal/registry"
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// maxBatchBody caps the size of a POST /batch request body.
+const maxBatchBody = 1 << 20
+
+// maxBatchV1Body caps the size of a POST /v1/batch request body; -max-batch
+// bounds the number of inputs in it.
+const maxBatchV1Body = 64 << 20
+
+// serveCommand defines the flags of "goofy serve" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func serveCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	gen.requestNamespaces = true
+	listen := fs.String("listen", "localhost:8080", "listen on `ADDR`, or on a Unix domain socket with unix:///PATH")
+	rateLimit := fs.Float64("rate", 0, "allow each client, by API key or else IP, `R` requests per second on average (0 for unlimited)")
+	burst := fs.Int("burst", 10, "allow each client, by API key or else IP, bursts of `N` requests on top of -rate")
+	grpcListen := fs.String("grpc-listen", "", "also serve the goofy.v1.IDService gRPC API on `ADDR`")
+	tlsCert := fs.String("tls-cert", "", "serve over TLS with the PEM certificate (chain) in `FILE`")
+	tlsKey := fs.String("tls-key", "", "PEM private key for -tls-cert in `FILE`")
+	tlsSelfSigned := fs.Bool("tls-self-signed", false, "serve over TLS with a generated self-signed certificate (development only)")
+	maxBatch := fs.Int("max-batch", 10000, "answer 413 to POST /v1/batch requests with more than `N` inputs")
+	drainTimeout := fs.Duration("shutdown-timeout", 30*time.Second, "on SIGINT or SIGTERM, wait up to `D` for in-flight requests to finish")
+	keysFile := fs.String("api-keys-file", "", "require an API key listed in `FILE` (\"LABEL KEY\" lines) on every request but the probes")
+	corsOrigins := fs.String("cors-origins", "", "let browser pages from the comma-separated `ORIGINS` call the HTTP API (* for any)")
+	corsMethods := fs.String("cors-methods", "GET, POST", "comma-separated `METHODS` allowed cross-origin, with -cors-origins")
+	corsHeaders := fs.String("cors-headers", "Content-Type, X-Api-Key, Authorization, X-Request-Id", "comma-separated request `HEADERS` allowed cross-origin, with -cors-origins")
+	accessLog := fs.Bool("access-log", false, "log every HTTP request and gRPC call with its request ID, status and latency")
+	regPath := fs.String("registry", "", "record every ID served in the registry database at `PATH` (or bolt:PATH, redis:// URL), refusing IDs that belong to another input")
+	ttl := addTTLFlag(fs, "registry entries", 0)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s serve [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Serve IDs over HTTP as JSON and, with -grpc-listen, over gRPC.\n\n")
+		fmt.Fprintf(os.Stderr, "Endpoints:\n")
+		fmt.Fprintf(os.Stderr, "  GET  /id?s=STRING  ID of a single string\n")
+		fmt.Fprintf(os.Stderr, "  POST /batch        IDs of a JSON array of strings\n")
+		fmt.Fprintf(os.Stderr, "  POST /v1/batch     IDs of a JSON array or NDJSON stream of strings, in order,\n")
+		fmt.Fprintf(os.Stderr, "                     with an error in place of each unreadable input\n")
+		fmt.Fprintf(os.Stderr, "  GET  /metrics      Prometheus metrics\n")
+		fmt.Fprintf(os.Stderr, "  GET  /openapi.json OpenAPI 3 description of these endpoints\n")
+		fmt.Fprintf(os.Stderr, "  GET  /healthz      liveness: 200 while the process serves requests\n")
+		fmt.Fprintf(os.Stderr, "  GET  /readyz       readiness: 503 while the -registry is unreachable\n")
+		fmt.Fprintf(os.Stderr, "  goofy.v1.IDService Generate, GenerateStream and BulkGenerate RPCs (-grpc-listen)\n\n")
+		fmt.Fprintf(os.Stderr, "A namespace query parameter (gRPC: the namespace field of the request)\n")
+		fmt.Fprintf(os.Stderr, "generates and registers IDs in that namespace instead of -namespace, so\n")
+		fmt.Fprintf(os.Stderr, "that one server hands out several independent ID spaces. With\n")
+		fmt.Fprintf(os.Stderr, "-namespace-ranges, only the namespaces listed there are accepted.\n\n")
+		fmt.Fprintf(os.Stderr, "With -api-keys-file, requests must carry a key in an X-Api-Key header or as\n")
+		fmt.Fprintf(os.Stderr, "\"Authorization: Bearer KEY\" (gRPC: x-api-key or authorization metadata).\n\n")
+		fmt.Fprintf(os.Stderr, "On SIGINT or SIGTERM the server stops accepting connections, answers 503\n")
+		fmt.Fprintf(os.Stderr, "on /readyz, waits up to -shutdown-timeout for in-flight requests and then\n")
+		fmt.Fprintf(os.Stderr, "closes the registry. A second signal exits at once.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s serve -listen :8080 -grpc-listen :9090\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s serve -listen unix:///run/goofy.sock\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s serve -tls-cert cert.pem -tls-key key.pem\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s serve -rate 5 -burst 20  # answer 429 beyond that per API key or client IP\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s serve -registry redis://redis:6379/0\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s serve -cors-origins https://tools.example.com\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  curl 'localhost:8080/id?s=hello+world'\n")
+		fmt.Fprintf(os.Stderr, "  curl 'localhost:8080/id?s=hello+world&namespace=billing'\n")
+		fmt.Fprintf(os.Stderr, "  curl -d '[\"a\",\"b\"]' localhost:8080/batch\n")
+		fmt.Fprintf(os.Stderr, "  jq -R . names.txt | curl -H 'Content-Type: application/x-ndjson' --data-binary @- localhost:8080/v1/batch\n")
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		var ranges map[string]goofy.Range
+		if *gen.ranges != "" {
+			ranges, _ = parseNamespaceRanges(*gen.ranges) // checked by gen.options
+		}
+
+		if *maxBatch < 1 {
+			fmt.Fprintf(os.Stderr, "Error: -max-batch must be positive\n\n")
+			fs.Usage()
+			return 1
+		}
+
+		tlsCfg, err := tlsConfig(*tlsCert, *tlsKey, *tlsSelfSigned, certHosts(*listen, *grpcListen))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		s := &server{
+			opts:      opts,
+			ranges:    ranges,
+			metrics:   newMetrics(),
+			limiter:   newRateLimiter(*rateLimit, *burst),
+			tls:       tlsCfg,
+			maxBatch:  *maxBatch,
+			accessLog: *accessLog,
+			started:   time.Now(),
+			cors:      newCORSPolicy(*corsOrigins, *corsMethods, *corsHeaders),
+			ttl:       time.Duration(*ttl),
+		}
+		if *keysFile != "" {
+			if s.keys, err = loadAPIKeys(*keysFile); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: loading API keys: %v\n", err)
+				return exitCode(err)
+			}
+			if s.limiter != nil {
+				s.limiter.keys = s.keys
+			}
+		}
+		if *regPath != "" {
+			s.reg, err = registry.Open(*regPath)
+			if err != nil {
+				slog.Error("opening registry", "err", err)
+				return 5
+			}
+			defer func() {
+				if err := s.reg.Close(); err != nil {
+					slog.Error("closing registry", "err", err)
+				}
+			}()
+		}
+		ln, err := openListener(*listen)
+		if err != nil {
+			slog.Error("opening listener", "err", err)
+			return 5
+		}
+		srv := &http.Server{
+			Handler:           s.routes(),
+			ReadHeaderTimeout: 10 * time.Second,
+			TLSConfig:         tlsCfg,
+			ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+		}
+		errc := make(chan error, 2)
+		go func() {
+			slog.Info("listening", "addr", *listen, "tls", tlsCfg != nil)
+			if tlsCfg != nil {
+				errc <- srv.ServeTLS(ln, "", "")
+				return
+			}
+			errc <- srv.Serve(ln)
+		}()
+
+		var gs *grpc.Server
+		if *grpcListen != "" {
+			gln, err := openListener(*grpcListen)
+			if err != nil {
+				srv.Close()
+				slog.Error("opening gRPC listener", "err", err)
+				return 5
+			}
+			gs = newGRPCServer(s)
+			go func() {
+				slog.Info("serving gRPC", "addr", *grpcListen)
+				errc <- gs.Serve(gln)
+			}()
+		}
+
+		// Either server failing takes the whole process down. On SIGINT or
+		// SIGTERM the listeners are closed, which also removes Unix sockets,
+		// and in-flight requests are drained; the deferred registry Close
+		// runs only once no handler can write to it anymore.
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		select {
+		case err := <-errc:
+			srv.Close()
+			if gs != nil {
+				gs.Stop()
+			}
+			slog.Error("serving", "err", err)
+			return 5
+		case <-ctx.Done():
+			stop() // a second signal kills the process
+			s.draining.Store(true)
+			slog.Info("shutting down", "timeout", *drainTimeout)
+			if !s.drain(srv, gs, *drainTimeout) {
+				slog.Warn("shutdown timed out, dropped in-flight requests", "timeout", *drainTimeout)
+			}
+			return 0
+		}
+	}
+}
+
+// server answers ID requests over HTTP.
+type server struct {
+	opts    goofy.Options
+	ranges  map[string]goofy.Range // nil unless -namespace-ranges
+	metrics *metrics
+	limiter *rateLimiter // nil unless -rate
+	tls     *tls.Config  // nil unless serving over TLS
+
+	maxBatch int               // most inputs in a POST /v1/batch request
+	reg      registry.Registry // nil unless -registry
+	ttl      time.Duration     // registry entries expire after ttl (-ttl) if positive
+	draining atomic.Bool       // shutting down: unready, finishing requests
+
+	keys      apiKeys       // nil unless -api-keys-file
+	cors      *corsPolicy   // nil unless -cors-origins
+	accessLog bool          // log every request (-access-log)
+	started   time.Time     // seeds generated request IDs
+	seq       atomic.Uint64 // requests given a generated ID so far
+}
+
+// drain stops srv and gs, if not nil, from accepting new connections and
+// waits up to timeout for their in-flight requests and streams to finish
+// before closing what remains. It reports whether everything finished in
+// time.
+func (s *server) drain(srv *http.Server, gs *grpc.Server, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if gs != nil {
+			gs.GracefulStop()
+		}
+	}()
+	if err := srv.Shutdown(ctx); err == nil {
+		select {
+		case <-done:
+			return true
+		case <-ctx.Done():
+		}
+	}
+	srv.Close()
+	if gs != nil {
+		gs.Stop()
+	}
+	<-done
+	return false
+}
+
+// routes returns the HTTP handler for all endpoints.
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/id", s.metrics.instrument("/id", s.requireKeyHTTP(s.limiter.limitHTTP(s.handleID))))
+	mux.Handle("/batch", s.metrics.instrument("/batch", s.requireKeyHTTP(s.limiter.limitHTTP(s.handleBatch))))
+	mux.Handle("/v1/batch", s.metrics.instrument("/v1/batch", s.requireKeyHTTP(s.limiter.limitHTTP(s.handleBatchV1))))
+	mux.Handle("/metrics", s.requireKeyHTTP(s.metrics.handler().ServeHTTP))
+	mux.Handle("/openapi.json", s.requireKeyHTTP(s.handleOpenAPI()))
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	h := s.cors.handler(mux)
+	if s.accessLog {
+		return s.logHTTP(h)
+	}
+	return h
+}
+
+// options returns the generator options of requests for namespace: the
+// server's own if it is empty, else those with namespace in place of
+// -namespace, confined to its range with -namespace-ranges.
+func (s *server) options(namespace string) (goofy.Options, error) {
+	opts := s.opts
+	if namespace == "" || namespace == opts.Namespace {
+		return opts, nil
+	}
+	opts.Namespace = namespace
+	opts.Range = nil
+	if s.ranges != nil {
+		r, ok := s.ranges[namespace]
+		if !ok {
+			return goofy.Options{}, fmt.Errorf("namespace %q has no range in -namespace-ranges", namespace)
+		}
+		opts.Range = &r
+	}
+	return opts, nil
+}
+
+// requestOptions returns the generator options of r, selected by its
+// namespace query parameter. If there are none, it answers 400 and
+// returns false.
+func (s *server) requestOptions(w http.ResponseWriter, r *http.Request) (goofy.Options, bool) {
+	opts, err := s.options(r.URL.Query().Get("namespace"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return goofy.Options{}, false
+	}
+	return opts, true
+}
+
+// record returns the record for input generated with opts, registering
+// its ID first with -registry in the namespace of opts, in digits like
+// goofy register does also with -nato. It fails with a
+// *registry.ConflictError if the ID belongs to another input of the
+// namespace, or with the *goofy.ExecError of a failing -algo exec: helper.
+func (s *server) record(ctx context.Context, input string, opts goofy.Options) (record, error) {
+	rec, err := newRecord(input, opts)
+	if err != nil {
+		return record{}, err
+	}
+	if s.reg != nil {
+		key := opts
+		key.NATO = false
+		id := goofy.GenerateFromSum64(rec.sum, key)
+		err := s.reg.Register(ctx, opts.Namespace, id, input, s.ttl)
+		var conflict *registry.ConflictError
+		if errors.As(err, &conflict) {
+			s.metrics.conflicts.Inc()
+		}
+		if err != nil {
+			return record{}, err
+		}
+	}
+	s.metrics.ids.Inc()
+	return rec, nil
+}
+
+// recordStatus returns the HTTP status for an error of s.record.
+func recordStatus(err error) int {
+	var conflict *registry.ConflictError
+	if errors.As(err, &conflict) {
+		return http.StatusConflict
+	}
+	return http.StatusServiceUnavailable
+}
+
+// The request and response bodies of the HTTP API. openapi.go derives the
+// schemas of /openapi.json from these types, so they are the API's contract.
+type (
+	// batchRequest is the body of POST /batch.
+	batchRequest []string
+
+	// batchResponse is the body of a successful POST /batch.
+	batchResponse struct {
+		Results []record `json:"results"`
+	}
+
+	// batchErrorResponse is the body of a POST /batch that failed on an
+	// input: the records of the inputs before it, which are registered
+	// with -registry, and why that input failed. The inputs after it
+	// were not tried.
+	batchErrorResponse struct {
+		Error   string   `json:"error"`
+		Results []record `json:"results"`
+	}
+
+	// batchV1Response is the body of a successful POST /v1/batch.
+	batchV1Response struct {
+		Results []batchResult `json:"results"`
+		Failed  int           `json:"failed"` // results with an error
+	}
+
+	// errorResponse is the body of every HTTP error response.
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+// handleID serves GET /id?s=STRING[&namespace=NAME].
+func (s *server) handleID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	q := r.URL.Query()
+	if !q.Has("s") {
+		writeError(w, http.StatusBadRequest, "missing query parameter s")
+		return
+	}
+	opts, ok := s.requestOptions(w, r)
+	if !ok {
+		return
+	}
+	rec, err := s.record(r.Context(), q.Get("s"), opts)
+	if err != nil {
+		writeError(w, recordStatus(err), err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, rec)
+}
+
+// handleBatch serves POST /batch with a JSON array of strings as body.
+func (s *server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	opts, ok := s.requestOptions(w, r)
+	if !ok {
+		return
+	}
+	var inputs batchRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBody)).Decode(&inputs); err != nil {
+		writeError(w, http.StatusBadRequest, "body must be a JSON array of strings: "+err.Error())
+		return
+	}
+	s.metrics.batchSize.WithLabelValues("/batch").Observe(float64(len(inputs)))
+	resp := batchResponse{Results: make([]record, 0, len(inputs))}
+	for i, input := range inputs {
+		rec, err := s.record(r.Context(), input, opts)
+		if err != nil {
+			writeJSON(w, recordStatus(err), batchErrorResponse{
+				Error:   fmt.Sprintf("element %d: %v", i+1, err),
+				Results: resp.Results,
+			})
+			return
+		}
+		resp.Results = append(resp.Results, rec)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// batchResult is the outcome for one input of POST /v1/batch: its record,
+// or why the input could not be read or registered.
+type batchResult struct {
+	*record
+	Error string `json:"error,omitempty"`
+}
+
+// handleBatchV1 serves POST /v1/batch. The body is either a JSON array of
+// strings or, when it does not start with '[', NDJSON with one JSON string
+// per line. Elements that are not strings, and with -registry inputs whose
+// ID could not be registered, fail on their own: the response
+// holds one result per input, in order, with an error in place of the
+// record for each that failed.
+func (s *server) handleBatchV1(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	opts, ok := s.requestOptions(w, r)
+	if !ok {
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBatchV1Body))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("body exceeds %d bytes", maxBatchV1Body))
+			return
+		}
+		writeError(w, http.StatusBadRequest, "reading body: "+err.Error())
+		return
+	}
+	elems, err := batchElements(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(elems) > s.maxBatch {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("batch of %d inputs exceeds the maximum of %d", len(elems), s.maxBatch))
+		return
+	}
+	s.metrics.batchSize.WithLabelValues("/v1/batch").Observe(float64(len(elems)))
+	resp := batchV1Response{Results: make([]batchResult, len(elems))}
+	for i, elem := range elems {
+		var input string
+		if err := json.Unmarshal(elem.raw, &input); err != nil {
+			resp.Results[i].Error = fmt.Sprintf("%s: not a JSON string", elem.pos)
+			resp.Failed++
+			continue
+		}
+		rec, err := s.record(r.Context(), input, opts)
+		if err != nil {
+			resp.Results[i].Error = fmt.Sprintf("%s: %v", elem.pos, err)
+			resp.Failed++
+			continue
+		}
+		resp.Results[i].record = &rec
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// batchElement is one undecoded input of a POST /v1/batch body.
+type batchElement struct {
+	raw []byte
+	pos string // "element N" or "line N", for error messages
+}
+
+// batchElements splits a POST /v1/batch body into its inputs. Blank NDJSON
+// lines are skipped; only a body that is not a JSON array or NDJSON at all
+// is an error.
+func batchElements(body []byte) ([]batchElement, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var raws []json.RawMessage
+		if err := json.Unmarshal(trimmed, &raws); err != nil {
+			return nil, fmt.Errorf("body must be a JSON array or NDJSON: %v", err)
+		}
+		elems := make([]batchElement, len(raws))
+		for i, raw := range raws {
+			elems[i] = batchElement{raw, fmt.Sprintf("element %d", i+1)}
+		}
+		return elems, nil
+	}
+	var elems []batchElement
+	for i, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		elems = append(elems, batchElement{line, fmt.Sprintf("line %d", i+1)})
+	}
+	return elems, nil
+}
+
+// writeJSON writes v as the JSON response body.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorResponse{msg})
+}
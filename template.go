@@ -0,0 +1,98 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// templateData is what -format templates are executed with.
+type templateData struct {
+	Input     string // the input string
+	ID        string // the bare ID, e.g. "259144"
+	Formatted string // the ID as printed by default, e.g. "25 91 44"
+	Algo      string // the hash algorithm
+	Namespace string // the namespace, if any
+	Counter   int    // the alternative with -alts, 0 for the regular ID
+	Digest    string // the full hex digest, for blake3 and sha256
+	Truncated bool   // whether the preprocessed Input exceeded -max-bytes
+	Hashed    string // the part of the preprocessed Input that was hashed
+}
+
+// templateWriter renders each record through a text/template.
+type templateWriter struct {
+	w    *bufio.Writer
+	tmpl *template.Template
+	opts goofy.Options
+	term byte // record terminator
+}
+
+// formatEscapes expands the backslash escapes accepted in -format, so
+// that '{{.Input}}\t{{.ID}}' works without $'...' quoting.
+var formatEscapes = strings.NewReplacer(`\\`, `\`, `\t`, "\t", `\n`, "\n")
+
+// newTemplateWriter returns a recordWriter executing text for each
+// record generated under opts.
+func newTemplateWriter(w io.Writer, text string, opts goofy.Options, nul bool) (*templateWriter, error) {
+	tmpl, err := template.New("format").Parse(formatEscapes.Replace(text))
+	if err != nil {
+		return nil, fmt.Errorf("parsing -format: %w", err)
+	}
+	tw := &templateWriter{w: bufio.NewWriter(w), tmpl: tmpl, opts: opts, term: '\n'}
+	if nul {
+		tw.term = 0
+	}
+	return tw, nil
+}
+
+func (t *templateWriter) Write(rec record) error {
+	bare := rec.options(t.opts)
+	bare.Spaced = false
+	bare.NATO = false
+	algo := t.opts.Algo
+	if algo == "" {
+		algo = goofy.DefaultAlgo
+	}
+	hashed, _ := goofy.HashedInput(rec.data, t.opts)
+	data := templateData{
+		Input:     rec.Input,
+		ID:        goofy.GenerateFromSum64(rec.sum, bare),
+		Formatted: rec.ID,
+		Algo:      algo,
+		Namespace: rec.Namespace,
+		Counter:   bare.Counter,
+		Digest:    rec.Digest,
+		Truncated: rec.Truncated,
+		Hashed:    hashed,
+	}
+	if err := t.tmpl.Execute(t.w, data); err != nil {
+		return err
+	}
+	return t.w.WriteByte(t.term)
+}
+
+func (t *templateWriter) Flush() error {
+	return t.w.Flush()
+}
@@ -0,0 +1,145 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// tokenKey returns the key tokens are signed with under opts, failing if
+// opts cannot make tokens.
+func tokenKey(opts goofy.Options) ([]byte, error) {
+	if len(opts.Key) == 0 {
+		return nil, fmt.Errorf("tokens require -key (or $GOOFY_KEY)")
+	}
+	if strings.Contains(opts.Alphabet, ".") {
+		return nil, fmt.Errorf("-alphabet must not contain \".\", which separates the parts of tokens")
+	}
+	return opts.Key, nil
+}
+
+// tokenCommand defines the flags of "goofy token" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func tokenCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	ttl := addTTLFlag(fs, "tokens", 15*time.Minute)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s token [options] <string>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print a self-contained token for each string that expires after -ttl:\n")
+		fmt.Fprintf(os.Stderr, "ID.EXPIRY.TAG, i.e. the plain ID, the expiry in seconds since the Unix\n")
+		fmt.Fprintf(os.Stderr, "epoch and an HMAC-SHA256 tag over both under -key. Anyone holding the\n")
+		fmt.Fprintf(os.Stderr, "key can check a token offline with \"%s verify-token\".\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s token -key \"$SECRET\" alice@example.com\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s token -key \"$SECRET\" -ttl 24h order-1234\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() < 1 {
+			fmt.Fprintf(os.Stderr, "Error: missing required argument <string>\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		var key []byte
+		if err == nil {
+			key, err = tokenKey(opts)
+		}
+		if err == nil && *ttl <= 0 {
+			err = fmt.Errorf("-ttl must be positive, got %v", ttl)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		expires := time.Now().Add(time.Duration(*ttl))
+		for _, s := range fs.Args() {
+			fmt.Println(goofy.Token(s, expires, key, opts))
+		}
+		return 0
+	}
+}
+
+// verifyTokenCommand defines the flags of "goofy verify-token" on fs and
+// returns the function running it once they are parsed, which returns the
+// process exit code.
+func verifyTokenCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	printID := fs.Bool("print", false, "print the ID and expiry a valid token carries")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s verify-token [options] <token> [<string>]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Check a token made by \"%s token\" under -key, rejecting tampered and\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "expired tokens. Given <string>, also check that the token carries its\n")
+		fmt.Fprintf(os.Stderr, "ID under the same generation options the token was made with.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s verify-token -key \"$SECRET\" \"$TOKEN\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify-token -key \"$SECRET\" \"$TOKEN\" alice@example.com\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - token is valid\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  2 - token is malformed, tampered with, expired or for another string\n")
+	}
+
+	return func() int {
+		if fs.NArg() < 1 || fs.NArg() > 2 {
+			fmt.Fprintf(os.Stderr, "Error: expected <token> and optionally <string>\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		var key []byte
+		if err == nil {
+			key, err = tokenKey(opts)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		id, expires, err := goofy.ParseToken(fs.Arg(0), key, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		if fs.NArg() == 2 && !goofy.Verify(fs.Arg(1), id, opts) {
+			fmt.Fprintf(os.Stderr, "Error: token is not for %q\n", fs.Arg(1))
+			return 2
+		}
+		if *printID {
+			fmt.Printf("%s\t%s\n", id, expires.Format(time.RFC3339))
+		}
+		return 0
+	}
+}
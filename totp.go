@@ -0,0 +1,97 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// totpCommand defines the flags of "goofy totp" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func totpCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	window := fs.Duration("window", 30*time.Second, "a code is valid for one time window of `DURATION` (whole seconds)")
+	skew := fs.Int("skew", 1, "with -verify, also accept the codes of `N` windows before and after the current one")
+	verify := fs.String("verify", "", "check that `ID` is a current code of <string> instead of printing one")
+	plain := fs.Bool("plain", false, "output as plain 6-digit string (default when stdout is not a terminal)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s totp [options] <string>\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s totp [options] -verify <ID> <string>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print a short confirmation code for <string> that changes every -window,\n")
+		fmt.Fprintf(os.Stderr, "like a TOTP: the number of the current window since the Unix epoch is\n")
+		fmt.Fprintf(os.Stderr, "mixed into the hash (see -rotate). With -verify, codes of the adjacent\n")
+		fmt.Fprintf(os.Stderr, "-skew windows are accepted too, allowing for clock drift and slow typing.\n")
+		fmt.Fprintf(os.Stderr, "Give a -key (or $GOOFY_KEY) so that only holders of the key can compute\n")
+		fmt.Fprintf(os.Stderr, "codes, and -at to use another time than now.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s totp -key \"$SECRET\" alice@example.com\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s totp -key \"$SECRET\" -verify 482913 alice@example.com\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s totp -window 5m -digits 8 order-1234\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - code printed, or -verify code valid\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  2 - -verify code invalid or expired\n")
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one <string>\n\n")
+			fs.Usage()
+			return 1
+		}
+		var err error
+		switch {
+		case onCommandLine(fs, "rotate"):
+			err = fmt.Errorf("-rotate cannot be combined with totp, use -window")
+		case *skew < 0:
+			err = fmt.Errorf("-skew must not be negative, got %d", *skew)
+		default:
+			err = fs.Set("rotate", window.String())
+		}
+		var opts goofy.Options
+		if err == nil {
+			opts, err = gen.options()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		if isSet(fs, "verify") {
+			if !goofy.VerifySkew(fs.Arg(0), *verify, opts, *skew) {
+				fmt.Fprintf(os.Stderr, "Error: code %s is invalid or expired\n", *verify)
+				return 2
+			}
+			return 0
+		}
+		opts.Spaced = !*plain && isTerminal(os.Stdout)
+		fmt.Println(goofy.Generate(fs.Arg(0), opts))
+		return 0
+	}
+}
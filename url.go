@@ -0,0 +1,95 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// urlFlags are the flags of -url, which hashes the resources at URLs
+// instead of the URLs themselves.
+type urlFlags struct {
+	enabled *bool
+	maxSize *int64
+	timeout *time.Duration
+}
+
+// addURLFlags registers the -url flags on fs.
+func addURLFlags(fs *flag.FlagSet) *urlFlags {
+	return &urlFlags{
+		enabled: fs.Bool("url", false, "treat inputs as http(s) URLs and derive IDs from the bytes downloaded from them"),
+		maxSize: fs.Int64("url-max-size", 64<<20, "with -url, fail on resources larger than `N` bytes"),
+		timeout: fs.Duration("url-timeout", 30*time.Second, "with -url, fail on downloads taking longer than `DURATION`"),
+	}
+}
+
+// fetcher returns the function fetching the resource at a URL as
+// selected by the flags, or nil without -url.
+func (u *urlFlags) fetcher() func(string) (string, error) {
+	if !*u.enabled {
+		return nil
+	}
+	client := &http.Client{Timeout: *u.timeout}
+	return func(rawURL string) (string, error) {
+		return fetchURL(context.Background(), client, rawURL, *u.maxSize)
+	}
+}
+
+// fetchURL downloads the resource at rawURL, failing on responses other
+// than 2xx and on bodies longer than maxSize bytes.
+func fetchURL(ctx context.Context, client *http.Client, rawURL string, maxSize int64) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		// Not a *url.Error: that would count as an I/O error.
+		return "", fmt.Errorf("parsing %q: %v", rawURL, errors.Unwrap(err))
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%q is not an http or https URL", rawURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", ioError{fmt.Errorf("fetching %s: %s", rawURL, resp.Status)}
+	}
+	if resp.ContentLength > maxSize {
+		return "", ioError{fmt.Errorf("fetching %s: %d bytes exceed -url-max-size %d", rawURL, resp.ContentLength, maxSize)}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("fetching %s: %w", rawURL, err)
+	}
+	if int64(len(body)) > maxSize {
+		return "", ioError{fmt.Errorf("fetching %s: body exceeds -url-max-size %d", rawURL, maxSize)}
+	}
+	return string(body), nil
+}
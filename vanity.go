@@ -0,0 +1,230 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// vanityChunk is the number of candidates a vanity worker claims at a time.
+const vanityChunk = 4096
+
+// vanityCommand defines the flags of "goofy vanity" on fs and returns the
+// function running it once they are parsed, which returns the process
+// exit code.
+func vanityCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	want := fs.String("want", "", "search for `ID`, or for IDs starting with it (required)")
+	charset := fs.String("suffix-charset", "a-z0-9", "build suffixes from the characters in `SET`, with ranges such as a-z")
+	maxLen := fs.Int("max-suffix", 8, "try suffixes of up to `N` characters")
+	jobs := fs.Int("jobs", runtime.NumCPU(), "search on `N` goroutines")
+	timeout := fs.Duration("timeout", time.Minute, "give up after `DURATION`")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s vanity [options] -want ID <base>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Search for a short suffix that, appended to <base>, gives an input whose\n")
+		fmt.Fprintf(os.Stderr, "ID is -want or starts with it, and print that input and its ID. Shorter\n")
+		fmt.Fprintf(os.Stderr, "suffixes are tried first, in charset order, so the result does not\n")
+		fmt.Fprintf(os.Stderr, "depend on -jobs. Each digit of -want takes about ten times as long\n")
+		fmt.Fprintf(os.Stderr, "to find.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s vanity -want 123456 release-\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s vanity -want 42 -suffix-charset 0-9 -max-suffix 4 order-\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - suffix found\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  2 - no suffix found within -max-suffix or -timeout\n")
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one <base>\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		v := &vanity{base: fs.Arg(0), want: *want, opts: opts}
+		if v.alphabet, err = parseCharset(*charset); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: -suffix-charset: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		if err := v.setup(*maxLen, *jobs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		defer cancel()
+		start := time.Now()
+		suffix, tried, ok := v.search(ctx, *jobs)
+		slog.Info("searched", "candidates", tried, "elapsed", time.Since(start).Round(time.Millisecond))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: no suffix found for ID %s\n", v.want)
+			return 2
+		}
+		input := v.base + suffix
+		fmt.Printf("%s\t%s\n", input, goofy.Generate(input, v.opts))
+		return 0
+	}
+}
+
+// vanity is a search for a suffix giving an ID that starts with want.
+// Candidate n is the suffix numbered n in bijective base len(alphabet):
+// all suffixes of length 1, then of length 2, and so on.
+type vanity struct {
+	base     string
+	want     string
+	opts     goofy.Options
+	alphabet []rune
+	limit    uint64 // number of candidates up to the longest suffix
+}
+
+// setup validates the search and computes its limit, shortening maxLen
+// so that no suffix reaches past -max-bytes, where it would not be hashed.
+func (v *vanity) setup(maxLen, jobs int) error {
+	if v.want == "" {
+		return fmt.Errorf("-want is required")
+	}
+	if jobs < 1 {
+		return fmt.Errorf("-jobs must be at least 1, got %d", jobs)
+	}
+	if maxLen < 1 {
+		return fmt.Errorf("-max-suffix must be at least 1, got %d", maxLen)
+	}
+	v.opts.Spaced = false
+	v.opts.NATO = false
+	if got := goofy.Generate("", v.opts); len(v.want) > len(got) {
+		return fmt.Errorf("-want %s is longer than the %d symbols of an ID", v.want, len(got))
+	}
+	longest := string(v.alphabet[len(v.alphabet)-1])
+	for ; maxLen > 0; maxLen-- {
+		if _, truncated := goofy.HashedInput(v.base+strings.Repeat(longest, maxLen), v.opts); !truncated {
+			break
+		}
+	}
+	if maxLen == 0 {
+		return fmt.Errorf("<base> leaves no room for a suffix within -max-bytes %d", v.opts.MaxBytes)
+	}
+	k := float64(len(v.alphabet))
+	total := 0.0
+	for l := 1; l <= maxLen; l++ {
+		total += math.Pow(k, float64(l))
+	}
+	v.limit = uint64(min(total, math.MaxUint64/2))
+	return nil
+}
+
+// suffix returns candidate n.
+func (v *vanity) suffix(n uint64) string {
+	k := uint64(len(v.alphabet))
+	var rs []rune
+	for n++; n > 0; n = (n - 1) / k {
+		rs = append(rs, v.alphabet[(n-1)%k])
+	}
+	for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+		rs[i], rs[j] = rs[j], rs[i]
+	}
+	return string(rs)
+}
+
+// search tries the candidates on jobs goroutines until ctx is done and
+// returns the first match in candidate order, if any, and the number of
+// candidates tried.
+func (v *vanity) search(ctx context.Context, jobs int) (suffix string, tried uint64, ok bool) {
+	var next, count atomic.Uint64
+	var best atomic.Uint64
+	best.Store(math.MaxUint64)
+	var wg sync.WaitGroup
+	for range jobs {
+		wg.Go(func() {
+			for ctx.Err() == nil {
+				lo := next.Add(vanityChunk) - vanityChunk
+				if lo >= v.limit || lo >= best.Load() {
+					return
+				}
+				hi := min(lo+vanityChunk, v.limit)
+				for n := lo; n < hi; n++ {
+					if strings.HasPrefix(goofy.Generate(v.base+v.suffix(n), v.opts), v.want) {
+						for cur := best.Load(); n < cur && !best.CompareAndSwap(cur, n); cur = best.Load() {
+						}
+						break
+					}
+				}
+				count.Add(hi - lo)
+			}
+		})
+	}
+	wg.Wait()
+	if n := best.Load(); n != math.MaxUint64 {
+		return v.suffix(n), count.Load(), true
+	}
+	return "", count.Load(), false
+}
+
+// parseCharset expands a -suffix-charset such as "a-z0-9_" into its
+// distinct characters, in order.
+func parseCharset(set string) ([]rune, error) {
+	rs := []rune(set)
+	var out []rune
+	seen := map[rune]bool{}
+	add := func(r rune) {
+		if !seen[r] {
+			seen[r] = true
+			out = append(out, r)
+		}
+	}
+	for i := 0; i < len(rs); i++ {
+		if i+2 < len(rs) && rs[i+1] == '-' {
+			if rs[i] > rs[i+2] {
+				return nil, fmt.Errorf("range %c-%c is reversed", rs[i], rs[i+2])
+			}
+			for r := rs[i]; r <= rs[i+2]; r++ {
+				add(r)
+			}
+			i += 2
+			continue
+		}
+		add(rs[i])
+	}
+	if len(out) < 2 {
+		return nil, fmt.Errorf("%q has fewer than two characters", set)
+	}
+	return out, nil
+}
@@ -0,0 +1,146 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// vectorKey keys the algorithms that require one in the test vectors.
+const vectorKey = "goofy-vector-key" // 16 bytes, as siphash needs
+
+// vectorInputs are the inputs of the test vectors. Besides everyday
+// strings they probe truncation at the default MaxBytes of 32 bytes,
+// including multibyte UTF-8 sequences straddling the limit.
+var vectorInputs = []string{
+	"",
+	"a",
+	"hello world!",
+	"The quick brown fox jumps over the lazy dog",
+	strings.Repeat("x", 32),
+	strings.Repeat("x", 33),
+	strings.Repeat("x", 31) + "é", // 2-byte sequence across byte 32
+	strings.Repeat("x", 30) + "€", // 3-byte sequence across byte 32
+	strings.Repeat("x", 29) + "😀", // 4-byte sequence across byte 32
+	"日本語のテキスト",
+	"tab\tand\nnewline",
+}
+
+// vector is one entry of the test vector file.
+type vector struct {
+	Input     string `json:"input"`
+	Algo      string `json:"algo"`
+	Key       string `json:"key,omitempty"`
+	Digits    int    `json:"digits"`
+	MaxBytes  int    `json:"max_bytes"` // -1 hashes the whole input
+	Hashed    string `json:"hashed"`    // the prefix of input that is hashed
+	Truncated bool   `json:"truncated"` // whether hashed is shorter than input
+	Sum64     string `json:"sum64"`     // decimal, as it exceeds JSON's safe integers
+	ID        string `json:"id"`
+}
+
+// vectorsCommand defines the flags of "goofy vectors" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func vectorsCommand(fs *flag.FlagSet) func() int {
+	out := fs.String("o", "-", "write the vectors to `FILE` (\"-\" for stdout)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s vectors [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Write a canonical JSON set of input/ID pairs across all hash algorithms,\n")
+		fmt.Fprintf(os.Stderr, "ID lengths and truncation edge cases, to validate reimplementations in\n")
+		fmt.Fprintf(os.Stderr, "other languages against. Keyed algorithms use the key given with each\n")
+		fmt.Fprintf(os.Stderr, "vector. IDs are plain decimal.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s vectors -o vectors.json\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() > 0 {
+			fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n\n", fs.Arg(0))
+			fs.Usage()
+			return 1
+		}
+
+		data, err := json.MarshalIndent(struct {
+			Version int      `json:"version"`
+			Vectors []vector `json:"vectors"`
+		}{1, testVectors()}, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		data = append(data, '\n')
+		if *out == "-" {
+			_, err = os.Stdout.Write(data)
+		} else {
+			err = os.WriteFile(*out, data, 0o644)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		return 0
+	}
+}
+
+// testVectors returns the test vectors: every input under every
+// algorithm at every decimal ID length, with the default truncation, and
+// once more hashing whole inputs.
+func testVectors() []vector {
+	var vs []vector
+	for _, algo := range goofy.Algorithms() {
+		var key string
+		if goofy.Keyed(algo) {
+			key = vectorKey
+		}
+		for _, maxBytes := range []int{goofy.MaxBytes, goofy.NoTruncation} {
+			for digits := goofy.MinDigits; digits <= goofy.MaxDigits; digits++ {
+				if maxBytes == goofy.NoTruncation && digits != goofy.DefaultDigits {
+					continue
+				}
+				opts := goofy.Options{Algo: algo, Key: []byte(key), Digits: digits, MaxBytes: maxBytes}
+				for _, input := range vectorInputs {
+					hashed, truncated := goofy.HashedInput(input, opts)
+					vs = append(vs, vector{
+						Input:     input,
+						Algo:      algo,
+						Key:       key,
+						Digits:    digits,
+						MaxBytes:  maxBytes,
+						Hashed:    hashed,
+						Truncated: truncated,
+						Sum64:     strconv.FormatUint(goofy.Sum64(input, opts), 10),
+						ID:        goofy.Generate(input, opts),
+					})
+				}
+			}
+		}
+	}
+	return vs
+}
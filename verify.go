@@ -0,0 +1,82 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/al-maisan/goofy/pkg/goofy"
+)
+
+// verifyCommand defines the flags of "goofy verify" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func verifyCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	nonce := fs.Int("nonce", 0, "verify an ID generated with -require-prefix, whose nonce was `N`")
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s verify [options] <string> <ID>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Recompute the ID of <string> and compare it with <ID>, honoring the\n")
+		fmt.Fprintf(os.Stderr, "same generation options as goofy itself. The ID may be given plain\n")
+		fmt.Fprintf(os.Stderr, "(259144) or spaced (\"25 91 44\").\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s verify \"hello world!\" 259144\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -digits 8 -salt staging \"hello world!\" 12345678\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -rotate daily -at 2026-10-16 pickup-42 357470\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s verify -nonce 106 alice 425160\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "\nExit codes:\n")
+		fmt.Fprintf(os.Stderr, "  0 - ID matches\n")
+		fmt.Fprintf(os.Stderr, "  1 - invalid usage\n")
+		fmt.Fprintf(os.Stderr, "  2 - ID does not match\n")
+	}
+
+	return func() int {
+		if fs.NArg() != 2 {
+			fmt.Fprintf(os.Stderr, "Error: expected <string> and <ID>\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err == nil {
+			opts.Counter = *nonce
+			err = opts.Validate()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+
+		ok, err := goofy.TryVerify(fs.Arg(0), fs.Arg(1), opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return exitCode(err)
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: ID %s does not match\n", fs.Arg(1))
+			return 2
+		}
+		return 0
+	}
+}
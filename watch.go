@@ -0,0 +1,175 @@
+// goofy - 6-digit hash ID generator
+// Copyright (C) 2025 Muharem Hrnjadovic <m@sky1.vip>
+//
+// SPDX-License-Identifier: GPL-3.0-or-later
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <https://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchCommand defines the flags of "goofy watch" on fs and returns
+// the function running it once they are parsed, which returns the process
+// exit code.
+func watchCommand(fs *flag.FlagSet) func() int {
+	gen := addGenFlags(fs)
+	out := addOutputFlags(fs, false)
+
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s watch [options] FILE\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Emit an ID for every line of FILE, then keep watching it and emit IDs\n")
+		fmt.Fprintf(os.Stderr, "for lines as they are appended. If FILE is truncated or replaced, its\n")
+		fmt.Fprintf(os.Stderr, "lines are emitted again from the start. Stop with Ctrl-C.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(os.Stderr, "\nExamples:\n")
+		fmt.Fprintf(os.Stderr, "  %s watch -echo names.txt\n", os.Args[0])
+	}
+
+	return func() int {
+		if fs.NArg() != 1 {
+			fmt.Fprintf(os.Stderr, "Error: expected exactly one FILE\n\n")
+			fs.Usage()
+			return 1
+		}
+		opts, err := gen.options()
+		if err == nil && *out.sort != "" {
+			// Sorting waits for the last record, which a watch never has.
+			err = errors.New("-sort cannot be combined with watch")
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n\n", err)
+			fs.Usage()
+			return 1
+		}
+		e, code := out.start(opts)
+		if e == nil {
+			return code
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return out.finish(e, watchFile(ctx, fs.Arg(0), e))
+	}
+}
+
+// watchFile emits the lines of path, and the lines later appended to it,
+// until ctx is done. The file's directory is watched rather than the file
+// itself so that a file replaced by rename or recreated is picked up too.
+func watchFile(ctx context.Context, path string, e *emitter) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	t := &tailer{path: path}
+	poll := func() error {
+		if err := t.poll(e.emit); err != nil {
+			return err
+		}
+		if e.errs != nil {
+			if err := e.errs.Flush(); err != nil {
+				return err
+			}
+		}
+		return e.out.Flush()
+	}
+	if err := poll(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-w.Errors:
+			return err
+		case ev := <-w.Events:
+			if filepath.Clean(ev.Name) != filepath.Clean(path) {
+				continue
+			}
+			if ev.Has(fsnotify.Write) || ev.Has(fsnotify.Create) {
+				if err := poll(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// tailer reads the complete lines appended to a file since the last poll.
+type tailer struct {
+	path   string
+	fi     os.FileInfo // the file seen at the last poll
+	offset int64       // end of the last complete line read
+}
+
+// poll calls fn for each complete line added to the file since the last
+// call. If the file was truncated or replaced it starts over. A missing
+// file is not an error; it may yet be created.
+func (t *tailer) poll(fn func(string) error) error {
+	f, err := os.Open(t.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if t.fi != nil && (!os.SameFile(t.fi, fi) || fi.Size() < t.offset) {
+		t.offset = 0
+	}
+	t.fi = fi
+	if _, err := f.Seek(t.offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return nil // an unterminated last line is read once complete
+		}
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", t.path, err)
+		}
+		t.offset += int64(len(line))
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+}